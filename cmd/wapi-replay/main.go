@@ -0,0 +1,194 @@
+// Command wapi-replay replays webhook notifications archived by manager.FileWebhookArchive through the
+// parsing of the webhook manager, printing the events they produce as JSON lines. It is used to check how
+// archived traffic is handled by a version of the SDK, and with -target to deliver archived notifications
+// again to a running webhook, with their original signature. The notifications are posted as archived, so
+// -target can not be combined with -phone or -event, which would need the notifications to be rewritten,
+// and signed again, to leave out the changes they filter out.
+//
+// Usage:
+//
+//	wapi-replay [-from time] [-to time] [-phone ids] [-event types] [-dry-run] archive.jsonl...
+//	wapi-replay [-from time] [-to time] [-target url] [-dry-run] archive.jsonl...
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gTahidi/wapi.go/internal/request_client"
+	"github.com/gTahidi/wapi.go/manager"
+	"github.com/gTahidi/wapi.go/pkg/events"
+)
+
+// printedEvent is a line of the output.
+type printedEvent struct {
+	ReceivedAt time.Time        `json:"received_at"`
+	Type       events.EventType `json:"type"`
+	Event      events.BaseEvent `json:"event"`
+}
+
+func main() {
+	from := flag.String("from", "", "replay the notifications received at or after this RFC 3339 time")
+	to := flag.String("to", "", "replay the notifications received at or before this RFC 3339 time")
+	phoneNumberIds := flag.String("phone", "", "comma separated ids of the business phone numbers to replay")
+	eventTypes := flag.String("event", "", "comma separated types of the events to replay, like text_message")
+	target := flag.String("target", "", "URL of a webhook to post the notifications producing a replayed event to, as archived")
+	dryRun := flag.Bool("dry-run", false, "print the events without posting anything to the target")
+	verbose := flag.Bool("v", false, "log the processing of the notifications to stderr")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] archive.jsonl...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *target != "" && (*phoneNumberIds != "" || *eventTypes != "") {
+		// the archived body, and its signature, would deliver the changes filtered out to the target too
+		fail(fmt.Errorf("-target can not be combined with -phone or -event, the notifications are posted whole"))
+	}
+
+	options := manager.WebhookReplayOptions{
+		PhoneNumberIds: splitList(*phoneNumberIds),
+		DryRun:         true,
+	}
+	for _, eventType := range splitList(*eventTypes) {
+		options.EventTypes = append(options.EventTypes, events.EventType(eventType))
+	}
+	var err error
+	if options.From, err = parseTime(*from); err != nil {
+		fail(err)
+	}
+	if options.To, err = parseTime(*to); err != nil {
+		fail(err)
+	}
+
+	level := slog.LevelError
+	if *verbose {
+		level = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	webhook := manager.NewWebhook(&manager.WebhookManagerConfig{
+		Secret:       "wapi-replay",
+		EventManager: manager.NewEventManager(),
+		Requester:    *request_client.NewRequestClient(""),
+		Logger:       logger,
+	})
+
+	// the events are only printed, the notifications producing some are posted to the target
+	encoder := json.NewEncoder(os.Stdout)
+	matched := false
+	options.OnEvent = func(record manager.ArchivedWebhook, eventType events.EventType, event events.BaseEvent) {
+		matched = true
+		if err := encoder.Encode(printedEvent{ReceivedAt: record.ReceivedAt, Type: eventType, Event: event}); err != nil {
+			logger.Error("error printing event", "type", eventType, "error", err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	result := manager.WebhookReplayResult{Events: map[events.EventType]int{}}
+	posted := 0
+	for _, path := range flag.Args() {
+		file, err := os.Open(path)
+		if err != nil {
+			fail(err)
+		}
+		err = manager.ReadWebhookArchive(file, func(record manager.ArchivedWebhook) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			matched = false
+			recordResult, err := webhook.ReplayWebhook(ctx, record, options)
+			result.Add(recordResult)
+			if err != nil {
+				logger.Error("error replaying webhook", "error", err)
+				return nil
+			}
+			if matched && *target != "" && !*dryRun {
+				if err := post(ctx, *target, record); err != nil {
+					return err
+				}
+				posted++
+			}
+			return nil
+		})
+		file.Close()
+		if err != nil {
+			fail(err)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "records: %d, replayed: %d, failed: %d, posted: %d\n", result.Records, result.Replayed, result.Failed, posted)
+	eventCounts := make([]string, 0, len(result.Events))
+	for eventType, count := range result.Events {
+		eventCounts = append(eventCounts, fmt.Sprintf("%s: %d", eventType, count))
+	}
+	sort.Strings(eventCounts)
+	for _, eventCount := range eventCounts {
+		fmt.Fprintln(os.Stderr, eventCount)
+	}
+	if result.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// post delivers an archived notification to a webhook, with the headers it was received with.
+func post(ctx context.Context, target string, record manager.ArchivedWebhook) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewBufferString(record.Body))
+	if err != nil {
+		return err
+	}
+	for name, values := range record.Header {
+		if name == "Content-Length" || name == "Host" {
+			continue
+		}
+		request.Header[name] = values
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("error posting webhook received at %s: %w", record.ReceivedAt.Format(time.RFC3339), err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("error posting webhook received at %s: status %d", record.ReceivedAt.Format(time.RFC3339), response.StatusCode)
+	}
+	return nil
+}
+
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected RFC 3339 like 2024-01-02T15:04:05Z", value)
+	}
+	return parsed, nil
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "wapi-replay:", err)
+	os.Exit(1)
+}
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/gTahidi/wapi.go/pkg/business"
@@ -219,5 +223,12 @@ func main() {
 		fmt.Println("image message event received")
 	})
 
-	client.Initiate()
+	// the webhook server is shut down gracefully on SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := client.Start(ctx); err != nil {
+		fmt.Println("error starting the webhook server:", err)
+		return
+	}
+	client.Wait()
 }
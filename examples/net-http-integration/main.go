@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	wapi "github.com/gTahidi/wapi.go/pkg/client"
+	"github.com/gTahidi/wapi.go/pkg/components"
+	"github.com/gTahidi/wapi.go/pkg/events"
+)
+
+func main() {
+
+	client := wapi.New(&wapi.ClientConfig{
+		ApiAccessToken:    "",
+		BusinessAccountId: "",
+		WebhookSecret:     "1234567890",
+		AppSecret:         "",
+	})
+
+	client.On(events.TextMessageEventType, func(event events.BaseEvent) {
+		textMessageEvent := event.(*events.TextMessageEvent)
+		reply, err := components.NewTextMessage(components.TextMessageConfigs{
+			Text: "Hello, from wapi.go",
+		})
+		if err != nil {
+			fmt.Println("error creating text message", err)
+			return
+		}
+		textMessageEvent.Reply(reply)
+	})
+
+	// the webhook is a standard http.Handler, it can be mounted the same way on chi or any other router
+	mux := http.NewServeMux()
+	mux.Handle("/webhook", client.WebhookHandler())
+
+	http.ListenAndServe(":8080", mux)
+}
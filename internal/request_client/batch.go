@@ -0,0 +1,233 @@
+package request_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// MaxBatchSize is the maximum number of operations the Graph API accepts in a single batch request.
+const MaxBatchSize = 50
+
+// ErrBatchOperationSkipped is the error of a batch operation which got no response, usually
+// because an operation it depends on failed.
+var ErrBatchOperationSkipped = errors.New("batch operation was not executed")
+
+// Batch queues api requests to send them to the Graph API in a single batch request,
+// see https://developers.facebook.com/docs/graph-api/batch-requests.
+type Batch struct {
+	requester  *RequestClient
+	operations []*BatchOperation
+}
+
+// BatchOperation is a request queued in a batch.
+type BatchOperation struct {
+	request   *ApiRequest
+	name      string
+	dependsOn string
+	// omitResponseOnSuccess is only set by SetOmitResponseOnSuccess, the named operations are otherwise sent
+	// with false, so that a missing response always means the operation was skipped
+	omitResponseOnSuccess *bool
+}
+
+// BatchResponse is the response to a single operation of a batch.
+type BatchResponse struct {
+	StatusCode int
+	Headers    http.Header
+	Body       string
+	// Err is set when the operation failed, it is a *GraphAPIError when the Graph API returned an error.
+	Err error
+}
+
+// Decode unmarshals the body of the response into v, or returns the error of the operation if it failed.
+func (response BatchResponse) Decode(v interface{}) error {
+	if response.Err != nil {
+		return response.Err
+	}
+	if err := json.Unmarshal([]byte(response.Body), v); err != nil {
+		return fmt.Errorf("error unmarshalling batch response: %w", err)
+	}
+	return nil
+}
+
+// NewBatch creates an empty batch which sends its operations with this request client.
+func (client *RequestClient) NewBatch() *Batch {
+	return &Batch{requester: client}
+}
+
+// Add queues the request in the batch and returns the queued operation, so that it can be named.
+func (batch *Batch) Add(request *ApiRequest) *BatchOperation {
+	operation := &BatchOperation{request: request}
+	batch.operations = append(batch.operations, operation)
+	return operation
+}
+
+// Len returns the number of queued operations.
+func (batch *Batch) Len() int {
+	return len(batch.operations)
+}
+
+// SetName names the operation, so that other operations can depend on it and reference its result
+// with JSONPath expressions, for example "{result=name:$.id}".
+func (operation *BatchOperation) SetName(name string) *BatchOperation {
+	operation.name = name
+	return operation
+}
+
+// SetDependsOn makes the operation run only after the operation with the given name succeeded.
+func (operation *BatchOperation) SetDependsOn(name string) *BatchOperation {
+	operation.dependsOn = name
+	return operation
+}
+
+// SetOmitResponseOnSuccess sets whether the response of a successful named operation is left out of the batch response.
+// The operations without response are then reported as successful, even when they were skipped because an
+// operation they depend on failed. The responses of the named operations are kept by default.
+func (operation *BatchOperation) SetOmitResponseOnSuccess(omit bool) *BatchOperation {
+	operation.omitResponseOnSuccess = &omit
+	return operation
+}
+
+// omitsResponseOnSuccess reports whether the Graph API was asked to leave the response of the operation out
+// when it succeeds.
+func (operation *BatchOperation) omitsResponseOnSuccess() bool {
+	return operation.omitResponseOnSuccess != nil && *operation.omitResponseOnSuccess
+}
+
+// omitResponseOnSuccessPayload returns the omit_response_on_success of the operation, which the Graph API
+// defaults to true for the named operations.
+func (operation *BatchOperation) omitResponseOnSuccessPayload() *bool {
+	if operation.omitResponseOnSuccess == nil && operation.name != "" {
+		omit := false
+		return &omit
+	}
+	return operation.omitResponseOnSuccess
+}
+
+// batchOperationPayload is the wire format of an operation of a batch request.
+type batchOperationPayload struct {
+	Method                string `json:"method"`
+	RelativeUrl           string `json:"relative_url"`
+	Body                  string `json:"body,omitempty"`
+	Name                  string `json:"name,omitempty"`
+	DependsOn             string `json:"depends_on,omitempty"`
+	OmitResponseOnSuccess *bool  `json:"omit_response_on_success,omitempty"`
+}
+
+// batchResponsePayload is the wire format of the response to an operation of a batch request.
+type batchResponsePayload struct {
+	Code    int `json:"code"`
+	Headers []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"headers"`
+	Body string `json:"body"`
+}
+
+// formEncodeJsonBody converts the JSON object body of a request to the url encoded form expected in a batch operation.
+// Values which are not strings are sent JSON encoded, as the Graph API accepts for form parameters.
+func formEncodeJsonBody(body string) (string, error) {
+	if body == "" {
+		return "", nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(body), &fields); err != nil {
+		return "", fmt.Errorf("batch operation body must be a JSON object: %w", err)
+	}
+	values := url.Values{}
+	for key, value := range fields {
+		var stringValue string
+		if err := json.Unmarshal(value, &stringValue); err == nil {
+			values.Set(key, stringValue)
+		} else {
+			values.Set(key, string(value))
+		}
+	}
+	return values.Encode(), nil
+}
+
+// Execute sends the queued operations as one batch request and returns their responses, in the order they were added.
+// The returned error is only set when the batch request itself failed, the errors of the operations are in their responses.
+func (batch *Batch) Execute(ctx context.Context) ([]BatchResponse, error) {
+	if len(batch.operations) == 0 {
+		return []BatchResponse{}, nil
+	}
+	if len(batch.operations) > MaxBatchSize {
+		return nil, fmt.Errorf("batch has %d operations, the Graph API accepts at most %d", len(batch.operations), MaxBatchSize)
+	}
+
+	idempotent := true
+	payloads := make([]batchOperationPayload, 0, len(batch.operations))
+	for index, operation := range batch.operations {
+		body, err := formEncodeJsonBody(operation.request.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding batch operation %d: %w", index, err)
+		}
+		payloads = append(payloads, batchOperationPayload{
+			Method:                operation.request.Method,
+			RelativeUrl:           batch.requester.relativeUrl(operation.request.Path, operation.request.queryParams()),
+			Body:                  body,
+			Name:                  operation.name,
+			DependsOn:             operation.dependsOn,
+			OmitResponseOnSuccess: operation.omitResponseOnSuccessPayload(),
+		})
+		idempotent = idempotent && (operation.request.idempotent || isIdempotentMethod(operation.request.Method))
+	}
+
+	batchJson, err := json.Marshal(payloads)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling batch: %w", err)
+	}
+	form := url.Values{}
+	form.Set("batch", string(batchJson))
+	form.Set("include_headers", strconv.FormatBool(true))
+
+	response, err := batch.requester.send(ctx, outgoingRequest{
+		method: http.MethodPost,
+		url:    batch.requester.requestUrl("", nil),
+		body:   []byte(form.Encode()),
+		headers: map[string]string{
+			"Content-Type": "application/x-www-form-urlencoded",
+		},
+		authScheme: "Bearer",
+		idempotent: idempotent,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var responsePayloads []*batchResponsePayload
+	if err := json.Unmarshal([]byte(response), &responsePayloads); err != nil {
+		return nil, fmt.Errorf("error unmarshalling batch response: %w", err)
+	}
+
+	responses := make([]BatchResponse, len(batch.operations))
+	for index := range responses {
+		if index >= len(responsePayloads) || responsePayloads[index] == nil {
+			if batch.operations[index].omitsResponseOnSuccess() {
+				responses[index] = BatchResponse{StatusCode: http.StatusOK}
+			} else {
+				responses[index] = BatchResponse{Err: ErrBatchOperationSkipped}
+			}
+			continue
+		}
+		payload := responsePayloads[index]
+		headers := http.Header{}
+		for _, header := range payload.Headers {
+			headers.Add(header.Name, header.Value)
+		}
+		responses[index] = BatchResponse{
+			StatusCode: payload.Code,
+			Headers:    headers,
+			Body:       payload.Body,
+		}
+		if payload.Code < 200 || payload.Code >= 300 {
+			responses[index].Err = ParseGraphAPIError(payload.Code, []byte(payload.Body))
+		}
+	}
+	return responses, nil
+}
@@ -0,0 +1,178 @@
+package request_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// newBatchTestClient creates a request client sending its requests to a server answering every batch request
+// with the given body, and recording the operations of the last one.
+func newBatchTestClient(t *testing.T, responseBody string) (*RequestClient, *[]batchOperationPayload) {
+	t.Helper()
+	var operations []batchOperationPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("error parsing batch request: %v", err)
+		}
+		if err := json.Unmarshal([]byte(r.PostForm.Get("batch")), &operations); err != nil {
+			t.Errorf("error decoding batch operations: %v", err)
+		}
+		w.Write([]byte(responseBody))
+	}))
+	t.Cleanup(server.Close)
+	client := NewRequestClient("token",
+		WithRequestProtocol("http"),
+		WithBaseUrl(strings.TrimPrefix(server.URL, "http://")),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 1}),
+	)
+	return client, &operations
+}
+
+func TestBatchExecuteMapsResponses(t *testing.T) {
+	client, _ := newBatchTestClient(t, `[
+		{"code": 200, "headers": [{"name": "Content-Type", "value": "application/json"}], "body": "{\"id\": \"1\"}"},
+		{"code": 400, "body": "{\"error\": {\"message\": \"Invalid parameter\", \"code\": 100, \"fbtrace_id\": \"trace\"}}"},
+		null,
+		null,
+		null
+	]`)
+	batch := client.NewBatch()
+	batch.Add(client.NewApiRequest("1", http.MethodGet))
+	batch.Add(client.NewApiRequest("2", http.MethodGet)).SetName("second")
+	batch.Add(client.NewApiRequest("3", http.MethodGet)).SetName("third").SetDependsOn("second")
+	batch.Add(client.NewApiRequest("4", http.MethodGet)).SetName("fourth").SetOmitResponseOnSuccess(true)
+	batch.Add(client.NewApiRequest("5", http.MethodGet))
+	batch.Add(client.NewApiRequest("6", http.MethodGet))
+
+	responses, err := batch.Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != batch.Len() {
+		t.Fatalf("got %d responses, want %d", len(responses), batch.Len())
+	}
+
+	tests := []struct {
+		name       string
+		statusCode int
+		wantErr    error
+	}{
+		{"success", http.StatusOK, nil},
+		{"graph api error", http.StatusBadRequest, nil},
+		{"named operation without response", 0, ErrBatchOperationSkipped},
+		{"omitted response", http.StatusOK, nil},
+		{"unnamed operation without response", 0, ErrBatchOperationSkipped},
+		{"operation missing from the response", 0, ErrBatchOperationSkipped},
+	}
+	for index, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := responses[index]
+			if response.StatusCode != test.statusCode {
+				t.Errorf("got status %d, want %d", response.StatusCode, test.statusCode)
+			}
+			if test.wantErr != nil && !errors.Is(response.Err, test.wantErr) {
+				t.Errorf("got error %v, want %v", response.Err, test.wantErr)
+			}
+		})
+	}
+
+	var decoded struct {
+		Id string `json:"id"`
+	}
+	if err := responses[0].Decode(&decoded); err != nil || decoded.Id != "1" {
+		t.Errorf("Decode() = %v with id %q, want id 1", err, decoded.Id)
+	}
+	if got := responses[0].Headers.Get("Content-Type"); got != "application/json" {
+		t.Errorf("got Content-Type %q, want application/json", got)
+	}
+	graphApiError, ok := AsGraphAPIError(responses[1].Err)
+	if !ok || graphApiError.Code != 100 || graphApiError.FbtraceId != "trace" {
+		t.Errorf("got error %#v, want a GraphAPIError with code 100", responses[1].Err)
+	}
+	if err := responses[2].Decode(&decoded); !errors.Is(err, ErrBatchOperationSkipped) {
+		t.Errorf("Decode() of a skipped operation = %v, want %v", err, ErrBatchOperationSkipped)
+	}
+}
+
+func TestBatchExecuteOperationPayloads(t *testing.T) {
+	client, operations := newBatchTestClient(t, `[]`)
+	batch := client.NewBatch()
+	get := client.NewApiRequest("catalog/products", http.MethodGet)
+	get.AddQueryParam("fields", "id,name")
+	batch.Add(get)
+	post := client.NewApiRequest("catalog/products", http.MethodPost)
+	post.SetBody(`{"retailer_id": "sku-1", "price": 1000}`)
+	batch.Add(post).SetName("product")
+	batch.Add(client.NewApiRequest("{result=product:$.id}", http.MethodGet)).SetDependsOn("product").SetOmitResponseOnSuccess(true)
+
+	if _, err := batch.Execute(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(*operations) != 3 {
+		t.Fatalf("got %d operations, want 3", len(*operations))
+	}
+
+	first, second, third := (*operations)[0], (*operations)[1], (*operations)[2]
+	if first.Method != http.MethodGet || first.RelativeUrl != client.ApiVersion()+"/catalog/products?fields=id%2Cname" {
+		t.Errorf("got operation %s %s", first.Method, first.RelativeUrl)
+	}
+	if first.OmitResponseOnSuccess != nil {
+		t.Errorf("got omit_response_on_success %v on an unnamed operation, want it left out", *first.OmitResponseOnSuccess)
+	}
+	body, err := url.ParseQuery(second.Body)
+	if err != nil || body.Get("retailer_id") != "sku-1" || body.Get("price") != "1000" {
+		t.Errorf("got body %q, want the form encoded JSON body", second.Body)
+	}
+	if second.Name != "product" || second.OmitResponseOnSuccess == nil || *second.OmitResponseOnSuccess {
+		t.Errorf("named operation without explicit setting must be sent with omit_response_on_success false")
+	}
+	if third.DependsOn != "product" || third.OmitResponseOnSuccess == nil || !*third.OmitResponseOnSuccess {
+		t.Errorf("got depends_on %q and omit_response_on_success %v", third.DependsOn, third.OmitResponseOnSuccess)
+	}
+}
+
+func TestBatchExecuteRejectsTooManyOperations(t *testing.T) {
+	client, _ := newBatchTestClient(t, `[]`)
+	batch := client.NewBatch()
+	for range MaxBatchSize + 1 {
+		batch.Add(client.NewApiRequest("id", http.MethodGet))
+	}
+	if _, err := batch.Execute(context.Background()); err == nil {
+		t.Fatal("Execute() accepted more operations than MaxBatchSize")
+	}
+}
+
+func TestFormEncodeJsonBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    url.Values
+		wantErr bool
+	}{
+		{"empty", "", url.Values{}, false},
+		{"strings", `{"name": "Shirt", "currency": "USD"}`, url.Values{"name": {"Shirt"}, "currency": {"USD"}}, false},
+		{"numbers and objects", `{"price": 1000, "applinks": {"web": {"url": "https://example.com"}}}`, url.Values{"price": {"1000"}, "applinks": {`{"web": {"url": "https://example.com"}}`}}, false},
+		{"not an object", `["a"]`, nil, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoded, err := formEncodeJsonBody(test.body)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("formEncodeJsonBody() error = %v, want error %v", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+			got, _ := url.ParseQuery(encoded)
+			if got.Encode() != test.want.Encode() {
+				t.Errorf("formEncodeJsonBody() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
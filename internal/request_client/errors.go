@@ -0,0 +1,170 @@
+package request_client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// GraphAPIError is the error returned when the Graph API answers a request with an error object,
+// see https://developers.facebook.com/docs/graph-api/guides/error-handling.
+// Use errors.As to get hold of it from the error returned by a manager.
+type GraphAPIError struct {
+	// HttpStatusCode is the status code of the http response the error was read from.
+	HttpStatusCode int
+	Message        string
+	Type           string
+	Code           int
+	Subcode        int
+	// Title is the short, user facing title of the error, if any.
+	Title string
+	// UserMessage is the user facing description of the error, if any.
+	UserMessage string
+	// Details carries the error_data.details of the WhatsApp error, which is usually the most precise description.
+	Details string
+	// IsTransientFlag is the "is_transient" hint sent by the Graph API.
+	IsTransientFlag bool
+	FbtraceId       string
+	// RetryAfter is how long the Graph API asked to wait before sending the request again, if it did.
+	RetryAfter time.Duration
+	// Body is the raw body of the response.
+	Body string
+}
+
+// graphApiErrorEnvelope is the wire format of an error returned by the Graph API.
+type graphApiErrorEnvelope struct {
+	Error *struct {
+		Message        string `json:"message"`
+		Type           string `json:"type"`
+		Code           int    `json:"code"`
+		ErrorSubcode   int    `json:"error_subcode"`
+		ErrorUserTitle string `json:"error_user_title"`
+		ErrorUserMsg   string `json:"error_user_msg"`
+		IsTransient    bool   `json:"is_transient"`
+		FbtraceId      string `json:"fbtrace_id"`
+		ErrorData      struct {
+			MessagingProduct string `json:"messaging_product"`
+			Details          string `json:"details"`
+		} `json:"error_data"`
+	} `json:"error"`
+}
+
+// ParseGraphAPIError builds a GraphAPIError out of a response. If the body does not contain a
+// Graph API error object, the raw body is used as the message.
+func ParseGraphAPIError(httpStatusCode int, body []byte) *GraphAPIError {
+	graphApiError := &GraphAPIError{
+		HttpStatusCode: httpStatusCode,
+		Body:           string(body),
+	}
+
+	var envelope graphApiErrorEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Error == nil {
+		graphApiError.Message = strings.TrimSpace(string(body))
+		if graphApiError.Message == "" {
+			graphApiError.Message = http.StatusText(httpStatusCode)
+		}
+		return graphApiError
+	}
+
+	graphApiError.Message = envelope.Error.Message
+	graphApiError.Type = envelope.Error.Type
+	graphApiError.Code = envelope.Error.Code
+	graphApiError.Subcode = envelope.Error.ErrorSubcode
+	graphApiError.Title = envelope.Error.ErrorUserTitle
+	graphApiError.UserMessage = envelope.Error.ErrorUserMsg
+	graphApiError.Details = envelope.Error.ErrorData.Details
+	graphApiError.IsTransientFlag = envelope.Error.IsTransient
+	graphApiError.FbtraceId = envelope.Error.FbtraceId
+	return graphApiError
+}
+
+func (e *GraphAPIError) Error() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "API request failed with status %d", e.HttpStatusCode)
+	if e.Code != 0 {
+		fmt.Fprintf(&builder, ", code %d", e.Code)
+		if e.Subcode != 0 {
+			fmt.Fprintf(&builder, " (subcode %d)", e.Subcode)
+		}
+	}
+	fmt.Fprintf(&builder, ": %s", e.Message)
+	if e.Details != "" && e.Details != e.Message {
+		fmt.Fprintf(&builder, ": %s", e.Details)
+	}
+	if e.FbtraceId != "" {
+		fmt.Fprintf(&builder, " (fbtrace_id: %s)", e.FbtraceId)
+	}
+	return builder.String()
+}
+
+// error codes, see https://developers.facebook.com/docs/whatsapp/cloud-api/support/error-codes
+var (
+	rateLimitedErrorCodes = map[int]bool{
+		4:      true, // application request limit reached
+		17:     true, // user request limit reached
+		32:     true, // page request limit reached
+		613:    true, // calls within one hour exceeded
+		80007:  true, // WhatsApp Business Account rate limit hit
+		130429: true, // cloud api message throughput reached
+		131048: true, // spam rate limit hit
+		131056: true, // (business account, consumer account) pair rate limit hit
+	}
+	reEngagementErrorCodes = map[int]bool{
+		131047: true, // more than 24 hours have passed since the recipient last replied
+	}
+	authExpiredErrorCodes = map[int]bool{
+		102: true, // API session
+		190: true, // access token has expired or is invalid
+	}
+	recipientInvalidErrorCodes = map[int]bool{
+		131021: true, // recipient cannot be sender
+		131026: true, // message undeliverable
+		131030: true, // recipient phone number not in allowed list
+	}
+	transientErrorCodes = map[int]bool{
+		1:      true, // API unknown
+		2:      true, // API service
+		131000: true, // something went wrong
+		131016: true, // service unavailable
+		133004: true, // server temporarily unavailable
+	}
+)
+
+// IsRateLimited reports whether the request was rejected because of a rate or throughput limit.
+func (e *GraphAPIError) IsRateLimited() bool {
+	return rateLimitedErrorCodes[e.Code] || e.HttpStatusCode == http.StatusTooManyRequests
+}
+
+// IsReEngagementWindow reports whether the message was rejected because the customer service window
+// is closed, in which case only a template message can be sent.
+func (e *GraphAPIError) IsReEngagementWindow() bool {
+	return reEngagementErrorCodes[e.Code]
+}
+
+// IsAuthExpired reports whether the access token used for the request is expired or invalid.
+func (e *GraphAPIError) IsAuthExpired() bool {
+	return authExpiredErrorCodes[e.Code] || e.HttpStatusCode == http.StatusUnauthorized
+}
+
+// IsRecipientInvalid reports whether the message can not be delivered to the given recipient.
+func (e *GraphAPIError) IsRecipientInvalid() bool {
+	return recipientInvalidErrorCodes[e.Code]
+}
+
+// IsTransient reports whether the error is a temporary failure on the side of the Graph API, so
+// the same request may succeed later. Rate limit errors are reported by IsRateLimited instead.
+func (e *GraphAPIError) IsTransient() bool {
+	return e.IsTransientFlag || transientErrorCodes[e.Code] || e.HttpStatusCode >= http.StatusInternalServerError
+}
+
+// AsGraphAPIError returns the GraphAPIError wrapped in err, if any.
+func AsGraphAPIError(err error) (*GraphAPIError, bool) {
+	var graphApiError *GraphAPIError
+	if errors.As(err, &graphApiError) {
+		return graphApiError, true
+	}
+	return nil, false
+}
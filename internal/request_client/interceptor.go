@@ -0,0 +1,43 @@
+package request_client
+
+import (
+	"errors"
+	"net/http"
+)
+
+// RequestHandler sends a request to the Graph API and returns its response.
+type RequestHandler func(request *http.Request) (*http.Response, error)
+
+// Interceptor wraps every attempt at sending a request to the Graph API, both for JSON and multipart requests.
+// It can modify the request before calling next, inspect or replace the response returned by next, or
+// short-circuit the call by returning a response of its own without calling next.
+// An interceptor reading the response body must replace it with an unread copy.
+type Interceptor func(request *http.Request, next RequestHandler) (*http.Response, error)
+
+// errNoResponse is returned when an interceptor returns neither a response nor an error.
+var errNoResponse = errors.New("interceptor returned no response")
+
+// WithInterceptors appends interceptors to the chain of the request client.
+// The first interceptor is the outermost one, i.e. it sees the request first and the response last.
+func WithInterceptors(interceptors ...Interceptor) RequestClientOption {
+	return func(options *requestClientOptions) {
+		options.interceptors = append(options.interceptors, interceptors...)
+	}
+}
+
+// do sends the request through the interceptor chain.
+func (rc *RequestClient) do(request *http.Request) (*http.Response, error) {
+	handler := RequestHandler(rc.httpClient.Do)
+	for i := len(rc.interceptors) - 1; i >= 0; i-- {
+		interceptor, next := rc.interceptors[i], handler
+		handler = func(request *http.Request) (*http.Response, error) {
+			return interceptor(request, next)
+		}
+	}
+
+	response, err := handler(request)
+	if err == nil && response == nil {
+		return nil, errNoResponse
+	}
+	return response, err
+}
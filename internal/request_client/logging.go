@@ -0,0 +1,52 @@
+package request_client
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Attribute keys shared by every log record of the SDK, so that records about the same
+// phone number, message or Graph API request can be correlated.
+const (
+	LogKeyPhoneNumberId = "phone_number_id"
+	LogKeyMessageId     = "message_id"
+	LogKeyEventType     = "event_type"
+	LogKeyFbtraceId     = "fbtrace_id"
+)
+
+// discardHandler is a slog.Handler which drops every record.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// NewDiscardLogger returns a logger which drops every record, the SDK is silent unless a logger is configured.
+func NewDiscardLogger() *slog.Logger {
+	return slog.New(discardHandler{})
+}
+
+// LoggerOrDiscard returns logger, or a logger which drops every record if it is nil.
+func LoggerOrDiscard(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return NewDiscardLogger()
+	}
+	return logger
+}
+
+// WithLogger sets the logger used to report the requests sent to the Graph API.
+// Requests are logged at debug level and retries at warn level, bodies are never logged.
+func WithLogger(logger *slog.Logger) RequestClientOption {
+	return func(options *requestClientOptions) {
+		options.logger = logger
+	}
+}
+
+// Logger returns the logger of the request client, which drops every record when none was configured.
+func (client *RequestClient) Logger() *slog.Logger {
+	if client.logger == nil {
+		return NewDiscardLogger()
+	}
+	return client.logger
+}
@@ -1,10 +1,15 @@
 package request_client
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 )
 
 const (
@@ -22,9 +27,87 @@ const (
 
 // RequestClient represents a client for making requests to a cloud API.
 type RequestClient struct {
-	apiVersion     string
-	baseUrl        string
-	apiAccessToken string
+	apiVersion      string
+	baseUrl         string
+	requestProtocol string
+	tokens          *tokenSource
+	appSecret       string
+	httpClient      *http.Client
+	retryPolicy     RetryPolicy
+	interceptors    []Interceptor
+	logger          *slog.Logger
+}
+
+// RequestClientOption configures optional behaviour of a RequestClient.
+type RequestClientOption func(*requestClientOptions)
+
+type requestClientOptions struct {
+	httpClient      *http.Client
+	transport       http.RoundTripper
+	baseUrl         string
+	requestProtocol string
+	apiVersion      string
+	timeout         time.Duration
+	retryPolicy     *RetryPolicy
+	interceptors    []Interceptor
+	logger          *slog.Logger
+	tokenProvider   TokenProvider
+	appSecret       string
+}
+
+// WithHttpClient makes the request client use the given http.Client for every request,
+// so that connections are pooled and reused across calls.
+func WithHttpClient(httpClient *http.Client) RequestClientOption {
+	return func(options *requestClientOptions) {
+		options.httpClient = httpClient
+	}
+}
+
+// WithTransport sets the http.RoundTripper used to send requests.
+// It takes precedence over the transport of a client passed with WithHttpClient.
+func WithTransport(transport http.RoundTripper) RequestClientOption {
+	return func(options *requestClientOptions) {
+		options.transport = transport
+	}
+}
+
+// WithBaseUrl sets the host the requests are sent to. The value can either be a bare host
+// (e.g. "graph.facebook.com") or a full origin including the scheme (e.g. "http://127.0.0.1:8081"),
+// which is useful to point the SDK at a local mock of the Graph API.
+func WithBaseUrl(baseUrl string) RequestClientOption {
+	return func(options *requestClientOptions) {
+		options.baseUrl = baseUrl
+	}
+}
+
+// WithRequestProtocol sets the scheme used for requests, "https" by default.
+func WithRequestProtocol(protocol string) RequestClientOption {
+	return func(options *requestClientOptions) {
+		options.requestProtocol = protocol
+	}
+}
+
+// WithApiVersion sets the Graph API version, API_VERSION by default.
+func WithApiVersion(apiVersion string) RequestClientOption {
+	return func(options *requestClientOptions) {
+		options.apiVersion = apiVersion
+	}
+}
+
+// WithTimeout sets the default timeout of a single request, including reading the response body.
+// A zero value means no timeout.
+func WithTimeout(timeout time.Duration) RequestClientOption {
+	return func(options *requestClientOptions) {
+		options.timeout = timeout
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried, DefaultRetryPolicy by default.
+// Use NoRetryPolicy to disable retries.
+func WithRetryPolicy(policy RetryPolicy) RequestClientOption {
+	return func(options *requestClientOptions) {
+		options.retryPolicy = &policy
+	}
 }
 
 func (client *RequestClient) BaseUrl() string {
@@ -35,16 +118,83 @@ func (client *RequestClient) ApiVersion() string {
 	return client.apiVersion
 }
 
+// ApiAccessToken returns the access token requests are currently sent with, or an empty string
+// if the token provider fails to provide one.
 func (client *RequestClient) ApiAccessToken() string {
-	return client.apiAccessToken
+	token, _ := client.token(context.Background())
+	return token
 }
 
-// NewRequestClient creates a new instance of RequestClient.
-func NewRequestClient(apiAccessToken string) *RequestClient {
+// RequestProtocol returns the scheme used for requests.
+func (client *RequestClient) RequestProtocol() string {
+	return client.requestProtocol
+}
+
+// HttpClient returns the http.Client used to send requests.
+func (client *RequestClient) HttpClient() *http.Client {
+	return client.httpClient
+}
+
+// RetryPolicy returns the retry policy applied to requests which do not override it.
+func (client *RequestClient) RetryPolicy() RetryPolicy {
+	return client.retryPolicy
+}
+
+// NewRequestClient creates a new instance of RequestClient, which sends requests with the given static
+// access token unless a token provider is passed with WithTokenProvider.
+func NewRequestClient(apiAccessToken string, opts ...RequestClientOption) *RequestClient {
+	options := &requestClientOptions{
+		baseUrl:         BASE_URL,
+		requestProtocol: REQUEST_PROTOCOL,
+		apiVersion:      API_VERSION,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	// a full origin passed as base url carries its own scheme
+	if scheme, host, found := strings.Cut(options.baseUrl, "://"); found {
+		options.requestProtocol = scheme
+		options.baseUrl = host
+	}
+	options.baseUrl = strings.TrimSuffix(options.baseUrl, "/")
+
+	httpClient := options.httpClient
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	if options.transport != nil || options.timeout > 0 {
+		// copy the client so that the one passed by the caller is never mutated
+		clientCopy := *httpClient
+		if options.transport != nil {
+			clientCopy.Transport = options.transport
+		}
+		if options.timeout > 0 {
+			clientCopy.Timeout = options.timeout
+		}
+		httpClient = &clientCopy
+	}
+
+	var tokenProvider TokenProvider = StaticTokenProvider(apiAccessToken)
+	if options.tokenProvider != nil {
+		tokenProvider = options.tokenProvider
+	}
+
+	retryPolicy := DefaultRetryPolicy()
+	if options.retryPolicy != nil {
+		retryPolicy = *options.retryPolicy
+	}
+
 	return &RequestClient{
-		apiVersion:     API_VERSION,
-		baseUrl:        BASE_URL,
-		apiAccessToken: apiAccessToken,
+		apiVersion:      options.apiVersion,
+		baseUrl:         options.baseUrl,
+		requestProtocol: options.requestProtocol,
+		tokens:          &tokenSource{provider: tokenProvider},
+		appSecret:       options.appSecret,
+		httpClient:      httpClient,
+		retryPolicy:     retryPolicy,
+		interceptors:    options.interceptors,
+		logger:          LoggerOrDiscard(options.logger),
 	}
 }
 
@@ -54,53 +204,165 @@ type RequestCloudApiParams struct {
 	Path       string
 	Method     string
 	QueryParam map[string]string
+	// Idempotent marks a request whose method is not idempotent as safe to send more than once.
+	Idempotent  bool
+	RetryPolicy *RetryPolicy
 }
 
-func (requestClientInstance *RequestClient) request(params RequestCloudApiParams) (string, error) {
-	queryParamString := ""
-	if len(params.QueryParam) > 0 {
-		queryParamString = "?"
-		for key, value := range params.QueryParam {
-			// if first query param, don't add "&"
-			if queryParamString != "?" {
-				queryParamString += "&"
-				queryParamString += strings.Join([]string{queryParamString, key, "=", value}, "")
-			} else {
-				queryParamString += strings.Join([]string{key, "=", value}, "")
-			}
+// requestUrl builds the absolute url of the given api path.
+func (client *RequestClient) requestUrl(path string, queryParams map[string]string) string {
+	return strings.Join([]string{client.requestProtocol, "://", client.baseUrl, "/", client.relativeUrl(path, queryParams)}, "")
+}
+
+// relativeUrl builds the url of the given api path relative to the host, starting with the api version.
+func (client *RequestClient) relativeUrl(path string, queryParams map[string]string) string {
+	requestPath := strings.Join([]string{client.apiVersion, "/", strings.TrimPrefix(path, "/")}, "")
+
+	if len(queryParams) > 0 {
+		values := url.Values{}
+		for key, value := range queryParams {
+			values.Set(key, value)
 		}
+		// some callers embed query params directly in the path
+		separator := "?"
+		if strings.Contains(requestPath, "?") {
+			separator = "&"
+		}
+		requestPath += separator + values.Encode()
 	}
 
-	requestPath := strings.Join(
-		[]string{REQUEST_PROTOCOL, "://", requestClientInstance.baseUrl, "/", requestClientInstance.apiVersion, "/", params.Path, queryParamString}, "")
+	return requestPath
+}
+
+func (requestClientInstance *RequestClient) request(ctx context.Context, params RequestCloudApiParams) (string, error) {
+	return requestClientInstance.send(ctx, outgoingRequest{
+		method: params.Method,
+		url:    requestClientInstance.requestUrl(params.Path, params.QueryParam),
+		body:   []byte(params.Body),
+		headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+		authScheme:  "Bearer",
+		idempotent:  params.Idempotent,
+		retryPolicy: params.RetryPolicy,
+	})
+}
 
-	httpRequest, err := http.NewRequest(params.Method,
-		requestPath,
-		strings.NewReader(params.Body))
+// outgoingRequest is a request ready to be sent, possibly more than once.
+type outgoingRequest struct {
+	method  string
+	url     string
+	body    []byte
+	headers map[string]string
+	// authScheme is the scheme of the Authorization header carrying the access token, which is
+	// resolved for every attempt so that a rotated token is picked up by the retries.
+	authScheme  string
+	idempotent  bool
+	retryPolicy *RetryPolicy
+}
+
+// send sends the request, retrying it as allowed by the retry policy in use.
+func (rc *RequestClient) send(ctx context.Context, outgoing outgoingRequest) (string, error) {
+	policy := rc.retryPolicyFor(ctx, outgoing.retryPolicy)
+	idempotent := outgoing.idempotent || isIdempotentMethod(outgoing.method)
+
+	tokenRefreshed := false
+	for attempt := 1; ; attempt++ {
+		response, err := rc.sendOnce(ctx, outgoing)
+		if err == nil {
+			return response, nil
+		}
+		// an expired token is rejected before the request is processed, so it is sent again once with a fresh token
+		if !tokenRefreshed && rc.invalidateToken(err) {
+			tokenRefreshed = true
+			rc.Logger().WarnContext(ctx, "retrying graph api request with a refreshed access token", requestLogAttrs(outgoing, err)...)
+			// the attempt with the expired token does not count against the retry policy
+			attempt--
+			continue
+		}
+		if attempt >= policy.MaxAttempts || !shouldRetry(ctx, err, idempotent) {
+			rc.Logger().DebugContext(ctx, "graph api request failed", append(requestLogAttrs(outgoing, err), "attempts", attempt)...)
+			return "", err
+		}
+
+		delay := policy.backoff(attempt)
+		if graphApiError, ok := AsGraphAPIError(err); ok && graphApiError.RetryAfter > 0 {
+			if policy.MaxRetryAfter > 0 && graphApiError.RetryAfter > policy.MaxRetryAfter {
+				return "", err
+			}
+			delay = graphApiError.RetryAfter
+		}
+		rc.Logger().WarnContext(ctx, "retrying graph api request", append(requestLogAttrs(outgoing, err), "attempt", attempt, "delay", delay)...)
+		if sleep(ctx, delay) != nil {
+			return "", err
+		}
+	}
+}
+
+// sendOnce makes a single attempt at sending the request.
+func (rc *RequestClient) sendOnce(ctx context.Context, outgoing outgoingRequest) (string, error) {
+	httpRequest, err := http.NewRequestWithContext(ctx, outgoing.method, outgoing.url, bytes.NewReader(outgoing.body))
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("error creating request: %w", err)
 	}
-	httpRequest.Header.Set("Content-Type", "application/json")
-	httpRequest.Header.Set("Authorization", fmt.Sprintf("Bearer %s", requestClientInstance.apiAccessToken))
-	httpClient := &http.Client{}
-	response, err := httpClient.Do(httpRequest)
+	if outgoing.authScheme != "" {
+		token, err := rc.token(ctx)
+		if err != nil {
+			return "", err
+		}
+		httpRequest.Header.Set("Authorization", fmt.Sprintf("%s %s", outgoing.authScheme, token))
+		if rc.appSecret != "" {
+			query := httpRequest.URL.Query()
+			query.Set("appsecret_proof", appSecretProof(rc.appSecret, token))
+			httpRequest.URL.RawQuery = query.Encode()
+		}
+	}
+	for key, value := range outgoing.headers {
+		httpRequest.Header.Set(key, value)
+	}
+
+	start := time.Now()
+	response, err := rc.do(httpRequest)
 	if err != nil {
-		fmt.Println("Error while requesting cloud api", err)
-		return "", err
+		return "", fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer response.Body.Close()
+
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	rc.Logger().DebugContext(ctx, "graph api request completed",
+		"method", outgoing.method,
+		"path", httpRequest.URL.Path,
+		"status", response.StatusCode,
+		"duration", time.Since(start),
+		LogKeyFbtraceId, response.Header.Get("X-Fb-Trace-Id"),
+	)
+
+	// Check for non-2xx status codes
 	if response.StatusCode < 200 || response.StatusCode >= 300 {
-		return "", fmt.Errorf("API request failed with status %d: %s", response.StatusCode, string(body))
+		graphApiError := ParseGraphAPIError(response.StatusCode, body)
+		graphApiError.RetryAfter = retryAfter(response.Header)
+		return "", graphApiError
 	}
 
 	return string(body), nil
 }
 
+// requestLogAttrs returns the attributes describing a failed request in log records.
+func requestLogAttrs(outgoing outgoingRequest, err error) []any {
+	attrs := []any{"method", outgoing.method, "error", err}
+	if requestUrl, parseErr := url.Parse(outgoing.url); parseErr == nil {
+		attrs = append(attrs, "path", requestUrl.Path)
+	}
+	if graphApiError, ok := AsGraphAPIError(err); ok && graphApiError.FbtraceId != "" {
+		attrs = append(attrs, LogKeyFbtraceId, graphApiError.FbtraceId)
+	}
+	return attrs
+}
+
 func (client *RequestClient) NewApiRequest(path, method string) *ApiRequest {
 	return &ApiRequest{
 		Path:        path,
@@ -127,6 +389,8 @@ type ApiRequest struct {
 	Fields      []*ApiRequestQueryParamField // Changed to slice of pointers
 	QueryParams map[string]string
 	Requester   *RequestClient
+	idempotent  bool
+	retryPolicy *RetryPolicy
 }
 
 func (request *ApiRequest) AddField(field ApiRequestQueryParamField) *ApiRequestQueryParamField {
@@ -156,8 +420,19 @@ func (request *ApiRequest) SetBody(body string) {
 	request.Body = body
 }
 
-// Execute executes the request and returns the response.
-func (request *ApiRequest) Execute() (string, error) {
+// SetIdempotent marks the request as safe to be sent more than once, so that it is retried
+// like a GET request even though its method is POST.
+func (request *ApiRequest) SetIdempotent(idempotent bool) {
+	request.idempotent = idempotent
+}
+
+// SetRetryPolicy overrides the retry policy of the request client for this request only.
+func (request *ApiRequest) SetRetryPolicy(policy RetryPolicy) {
+	request.retryPolicy = &policy
+}
+
+// queryParams returns the query params of the request, including the fields.
+func (request *ApiRequest) queryParams() map[string]string {
 	// check if there are any fields in the request
 	var queryParam = map[string]string{}
 	if len(request.Fields) > 0 {
@@ -184,62 +459,85 @@ func (request *ApiRequest) Execute() (string, error) {
 		}
 	}
 
-	response, err := request.Requester.request(RequestCloudApiParams{
-		Path:       request.Path,
-		Body:       request.Body,
-		Method:     request.Method,
-		QueryParam: queryParam,
+	return queryParam
+}
+
+// Execute executes the request and returns the response.
+// The request is aborted as soon as ctx is done.
+// A non-2xx response is returned as a *GraphAPIError.
+func (request *ApiRequest) Execute(ctx context.Context) (string, error) {
+	response, err := request.Requester.request(ctx, RequestCloudApiParams{
+		Path:        request.Path,
+		Body:        request.Body,
+		Method:      request.Method,
+		QueryParam:  request.queryParams(),
+		Idempotent:  request.idempotent,
+		RetryPolicy: request.retryPolicy,
 	})
 
 	if err != nil {
-		fmt.Println("Error while executing business api request", err)
 		return "", err
 	}
 
 	return response, err
 }
 
+// RawRequestParams represents the parameters of a request whose body is sent as is.
+type RawRequestParams struct {
+	Method  string
+	Path    string
+	Body    io.Reader
+	Headers map[string]string
+	// AuthScheme replaces the default "Bearer" scheme of the Authorization header,
+	// for example the resumable upload API expects "OAuth".
+	AuthScheme string
+	// Idempotent marks a request whose method is not idempotent as safe to send more than once.
+	Idempotent  bool
+	RetryPolicy *RetryPolicy
+}
+
+// RequestRaw sends an arbitrary body to the given path, with the provided headers set on the request.
+// This is needed for endpoints which do not accept JSON, like binary file uploads.
+// A non-2xx response is returned as a *GraphAPIError.
+func (rc *RequestClient) RequestRaw(ctx context.Context, params RawRequestParams) (string, error) {
+	// the body is read upfront so that it can be sent again when the request is retried
+	var body []byte
+	if params.Body != nil {
+		var err error
+		if body, err = io.ReadAll(params.Body); err != nil {
+			return "", fmt.Errorf("error reading request body: %w", err)
+		}
+	}
+
+	authScheme := params.AuthScheme
+	if authScheme == "" {
+		authScheme = "Bearer"
+	}
+
+	return rc.send(ctx, outgoingRequest{
+		method:      params.Method,
+		url:         rc.requestUrl(params.Path, nil),
+		body:        body,
+		headers:     params.Headers,
+		authScheme:  authScheme,
+		idempotent:  params.Idempotent,
+		retryPolicy: params.RetryPolicy,
+	})
+}
+
 // RequestMultipart allows sending an arbitrary body with a custom Content-Type.
 // This is needed for file uploads (multipart/form-data).
 func (rc *RequestClient) RequestMultipart(
+	ctx context.Context,
 	method string,
 	path string,
 	body io.Reader,
 	contentType string,
 ) (string, error) {
-	// 1. Build the final URL
-	requestPath := strings.Join([]string{
-		REQUEST_PROTOCOL, "://",
-		rc.baseUrl, "/",
-		rc.apiVersion, "/",
-		path,
-	}, "")
-
-	// 2. Create the HTTP request with the custom body & Content-Type
-	httpRequest, err := http.NewRequest(method, requestPath, body)
-	if err != nil {
-		return "", fmt.Errorf("error creating request: %w", err)
-	}
-	httpRequest.Header.Set("Authorization", fmt.Sprintf("Bearer %s", rc.apiAccessToken))
-	httpRequest.Header.Set("Content-Type", contentType)
-
-	// 3. Send the request using a standard HTTP client
-	httpClient := &http.Client{}
-	response, err := httpClient.Do(httpRequest)
-	if err != nil {
-		return "", fmt.Errorf("failed to execute request: %w", err)
-	}
-	defer response.Body.Close()
-
-	respBody, err := io.ReadAll(response.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	// Check for non-2xx status codes
-	if response.StatusCode < 200 || response.StatusCode >= 300 {
-		return "", fmt.Errorf("API request failed with status %d: %s", response.StatusCode, string(respBody))
-	}
-
-	return string(respBody), nil
+	return rc.RequestRaw(ctx, RawRequestParams{
+		Method:  method,
+		Path:    path,
+		Body:    body,
+		Headers: map[string]string{"Content-Type": contentType},
+	})
 }
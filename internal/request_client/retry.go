@@ -0,0 +1,175 @@
+package request_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried.
+//
+// Requests with an idempotent method (GET, HEAD, PUT, DELETE) or explicitly marked as idempotent are
+// retried on throttling, 5xx, transient Graph API and network errors. Other requests, like sending a
+// message, are only retried when they were rejected before being processed (throttling errors or a
+// failed connection), so a message is never sent twice.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts of a request, 1 disables retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the delay grows by after every attempt.
+	Multiplier float64
+	// Jitter is the fraction of the delay, between 0 and 1, that is randomized.
+	Jitter float64
+	// MaxRetryAfter is the longest delay asked by the Graph API (Retry-After or usage headers) that is waited for,
+	// the error is returned straight away when the API asks to wait longer.
+	MaxRetryAfter time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.5,
+		MaxRetryAfter:  time.Minute,
+	}
+}
+
+// NoRetryPolicy returns a retry policy which never retries.
+func NoRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+// backoff returns the delay before the given retry, 1 being the first one.
+func (policy RetryPolicy) backoff(retry int) time.Duration {
+	multiplier := policy.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	delay := float64(policy.InitialBackoff) * math.Pow(multiplier, float64(retry-1))
+	if policy.MaxBackoff > 0 && delay > float64(policy.MaxBackoff) {
+		delay = float64(policy.MaxBackoff)
+	}
+	jitter := math.Min(math.Max(policy.Jitter, 0), 1)
+	delay = delay*(1-jitter) + rand.Float64()*delay*jitter
+	return time.Duration(delay)
+}
+
+type retryPolicyContextKey struct{}
+
+// ContextWithRetryPolicy returns a copy of ctx which makes the requests sent with it use the given retry policy,
+// instead of the one the request client is configured with.
+func ContextWithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyContextKey{}, policy)
+}
+
+// retryPolicyFor returns the retry policy to apply to a request sent with ctx.
+func (rc *RequestClient) retryPolicyFor(ctx context.Context, override *RetryPolicy) RetryPolicy {
+	if override != nil {
+		return *override
+	}
+	if policy, ok := ctx.Value(retryPolicyContextKey{}).(RetryPolicy); ok {
+		return policy
+	}
+	return rc.retryPolicy
+}
+
+// isIdempotentMethod reports whether sending a request with the given method twice has the same effect as sending it once.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// shouldRetry reports whether a request which failed with err can be sent again.
+func shouldRetry(ctx context.Context, err error, idempotent bool) bool {
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var graphApiError *GraphAPIError
+	if errors.As(err, &graphApiError) {
+		// throttled requests are rejected before being processed, so they are always safe to send again
+		if graphApiError.IsRateLimited() {
+			return true
+		}
+		return idempotent && graphApiError.IsTransient()
+	}
+
+	// the request never left when the connection could not be established
+	var opError *net.OpError
+	if errors.As(err, &opError) && opError.Op == "dial" {
+		return true
+	}
+
+	var netError net.Error
+	if errors.As(err, &netError) {
+		return idempotent
+	}
+	return false
+}
+
+// retryAfter reads the delay the Graph API asks to wait before sending a request again,
+// from the Retry-After header or from the estimated time to regain access of the usage headers.
+func retryAfter(header http.Header) time.Duration {
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+		if date, err := http.ParseTime(value); err == nil {
+			return time.Until(date)
+		}
+	}
+
+	var longest time.Duration
+	// X-Business-Use-Case-Usage: {"<business id>": [{"type": "whatsapp", "estimated_time_to_regain_access": 5, ...}]}
+	if value := header.Get("X-Business-Use-Case-Usage"); value != "" {
+		var usage map[string][]struct {
+			EstimatedTimeToRegainAccess int `json:"estimated_time_to_regain_access"`
+		}
+		if err := json.Unmarshal([]byte(value), &usage); err == nil {
+			for _, entries := range usage {
+				for _, entry := range entries {
+					longest = max(longest, time.Duration(entry.EstimatedTimeToRegainAccess)*time.Minute)
+				}
+			}
+		}
+	}
+	// X-Ad-Account-Usage and X-App-Usage share the same shape
+	for _, name := range []string{"X-App-Usage", "X-Ad-Account-Usage"} {
+		if value := header.Get(name); value != "" {
+			var usage struct {
+				EstimatedTimeToRegainAccess int `json:"estimated_time_to_regain_access"`
+			}
+			if err := json.Unmarshal([]byte(value), &usage); err == nil {
+				longest = max(longest, time.Duration(usage.EstimatedTimeToRegainAccess)*time.Minute)
+			}
+		}
+	}
+	return longest
+}
+
+// sleep waits for the given delay, returning early with the context error if ctx is done.
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,151 @@
+package request_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShouldRetry(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		err        error
+		idempotent bool
+		want       bool
+	}{
+		{"rate limited by code", context.Background(), &GraphAPIError{HttpStatusCode: 400, Code: 130429}, false, true},
+		{"rate limited by status", context.Background(), &GraphAPIError{HttpStatusCode: 429}, false, true},
+		{"wrapped rate limit", context.Background(), fmt.Errorf("sending: %w", &GraphAPIError{Code: 80007}), false, true},
+		{"transient idempotent", context.Background(), &GraphAPIError{HttpStatusCode: 503}, true, true},
+		{"transient not idempotent", context.Background(), &GraphAPIError{HttpStatusCode: 503}, false, false},
+		{"transient flag idempotent", context.Background(), &GraphAPIError{HttpStatusCode: 400, IsTransientFlag: true}, true, true},
+		{"invalid parameter", context.Background(), &GraphAPIError{HttpStatusCode: 400, Code: 100}, true, false},
+		{"expired token", context.Background(), &GraphAPIError{HttpStatusCode: 401, Code: 190}, true, false},
+		{"dial error", context.Background(), &net.OpError{Op: "dial", Err: errors.New("connection refused")}, false, true},
+		{"read error idempotent", context.Background(), &net.OpError{Op: "read", Err: errors.New("connection reset")}, true, true},
+		{"read error not idempotent", context.Background(), &net.OpError{Op: "read", Err: errors.New("connection reset")}, false, false},
+		{"other error", context.Background(), errors.New("boom"), true, false},
+		{"context cancelled", cancelled, &GraphAPIError{HttpStatusCode: 429}, true, false},
+		{"deadline exceeded", context.Background(), context.DeadlineExceeded, true, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := shouldRetry(test.ctx, test.err, test.idempotent); got != test.want {
+				t.Errorf("shouldRetry() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{"no header", http.Header{}, 0},
+		{"retry after seconds", http.Header{"Retry-After": {"7"}}, 7 * time.Second},
+		{"invalid retry after", http.Header{"Retry-After": {"soon"}}, 0},
+		{
+			"business use case usage",
+			http.Header{"X-Business-Use-Case-Usage": {`{"123": [{"type": "whatsapp", "estimated_time_to_regain_access": 5}, {"type": "other", "estimated_time_to_regain_access": 2}]}`}},
+			5 * time.Minute,
+		},
+		{"app usage", http.Header{"X-App-Usage": {`{"call_count": 100, "estimated_time_to_regain_access": 3}`}}, 3 * time.Minute},
+		{
+			"longest usage header",
+			http.Header{
+				"X-App-Usage":        {`{"estimated_time_to_regain_access": 3}`},
+				"X-Ad-Account-Usage": {`{"estimated_time_to_regain_access": 4}`},
+			},
+			4 * time.Minute,
+		},
+		{"retry after takes precedence", http.Header{"Retry-After": {"2"}, "X-App-Usage": {`{"estimated_time_to_regain_access": 3}`}}, 2 * time.Second},
+		{"invalid usage header", http.Header{"X-App-Usage": {"not json"}}, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := retryAfter(test.header); got != test.want {
+				t.Errorf("retryAfter() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRetryAfterDate(t *testing.T) {
+	header := http.Header{"Retry-After": {time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)}}
+	if got := retryAfter(header); got < 59*time.Minute || got > time.Hour {
+		t.Errorf("retryAfter() = %v, want about an hour", got)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+	for retry, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 4: 800 * time.Millisecond, 5: time.Second, 10: time.Second} {
+		if got := policy.backoff(retry); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", retry, got, want)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for range 100 {
+		if got := policy.backoff(2); got < 100*time.Millisecond || got > 200*time.Millisecond {
+			t.Fatalf("backoff(2) with jitter = %v, want between 100ms and 200ms", got)
+		}
+	}
+}
+
+func TestRequestRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		statuses     []int
+		wantAttempts int32
+		wantErr      bool
+	}{
+		{"get retried until success", http.MethodGet, []int{503, 500, 200}, 3, false},
+		{"get gives up after max attempts", http.MethodGet, []int{503, 503, 503, 503}, 3, true},
+		{"post not retried on server error", http.MethodPost, []int{500, 200}, 1, true},
+		{"post retried when throttled", http.MethodPost, []int{429, 200}, 2, false},
+		{"get not retried on bad request", http.MethodGet, []int{400, 200}, 1, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := test.statuses[attempts.Add(1)-1]
+				w.WriteHeader(status)
+				if status == http.StatusOK {
+					w.Write([]byte(`{"success": true}`))
+					return
+				}
+				fmt.Fprintf(w, `{"error": {"message": "failed", "code": %d}}`, status)
+			}))
+			defer server.Close()
+
+			client := NewRequestClient("token",
+				WithRequestProtocol("http"),
+				WithBaseUrl(strings.TrimPrefix(server.URL, "http://")),
+				WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, Multiplier: 1}),
+			)
+			_, err := client.NewApiRequest("123/messages", test.method).Execute(context.Background())
+			if (err != nil) != test.wantErr {
+				t.Errorf("Execute() error = %v, want error %v", err, test.wantErr)
+			}
+			if got := attempts.Load(); got != test.wantAttempts {
+				t.Errorf("got %d attempts, want %d", got, test.wantAttempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package request_client
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TokenProvider provides the access token of the requests sent to the Graph API. It is called for every
+// request, including every retry, so implementations backed by a remote service should cache the token.
+type TokenProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenInvalidator is implemented by the token providers which cache their token. The request client
+// invalidates the token when the Graph API rejects it as expired, and sends the request again with a fresh one.
+type TokenInvalidator interface {
+	Invalidate()
+}
+
+// StaticTokenProvider provides the same access token for every request.
+type StaticTokenProvider string
+
+// Token returns the static token.
+func (token StaticTokenProvider) Token(ctx context.Context) (string, error) {
+	return string(token), nil
+}
+
+// TokenProviderFunc adapts a function to the TokenProvider interface.
+type TokenProviderFunc func(ctx context.Context) (string, error)
+
+// Token calls the function.
+func (fn TokenProviderFunc) Token(ctx context.Context) (string, error) {
+	return fn(ctx)
+}
+
+// TokenFetchFunc fetches a fresh access token and the time it expires at, a zero time when it is unknown.
+type TokenFetchFunc func(ctx context.Context) (token string, expiresAt time.Time, err error)
+
+// CachingTokenProviderConfig configures a CachingTokenProvider.
+type CachingTokenProviderConfig struct {
+	// Fetch is called to get a fresh token when there is no cached token or it is about to expire.
+	Fetch TokenFetchFunc
+	// TTL is how long a token is cached when Fetch does not tell when it expires, a token is then cached until invalidated when zero.
+	TTL time.Duration
+	// RefreshBefore is how long before its expiry a token is refreshed, one minute by default.
+	RefreshBefore time.Duration
+}
+
+// CachingTokenProvider caches the token returned by a fetch function until it is about to expire,
+// or until it is invalidated because the Graph API rejected it.
+type CachingTokenProvider struct {
+	config    CachingTokenProviderConfig
+	mutex     sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewCachingTokenProvider creates a new instance of CachingTokenProvider.
+func NewCachingTokenProvider(config CachingTokenProviderConfig) *CachingTokenProvider {
+	if config.RefreshBefore <= 0 {
+		config.RefreshBefore = time.Minute
+	}
+	return &CachingTokenProvider{config: config}
+}
+
+// Token returns the cached token, fetching a new one if needed. Concurrent calls wait for a single fetch.
+func (provider *CachingTokenProvider) Token(ctx context.Context) (string, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	if provider.token != "" && (provider.expiresAt.IsZero() || time.Until(provider.expiresAt) > provider.config.RefreshBefore) {
+		return provider.token, nil
+	}
+	if provider.config.Fetch == nil {
+		return "", errors.New("caching token provider has no fetch function")
+	}
+
+	token, expiresAt, err := provider.config.Fetch(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error fetching access token: %w", err)
+	}
+	if token == "" {
+		return "", errors.New("error fetching access token: empty token")
+	}
+	if expiresAt.IsZero() && provider.config.TTL > 0 {
+		expiresAt = time.Now().Add(provider.config.TTL)
+	}
+	provider.token = token
+	provider.expiresAt = expiresAt
+	return token, nil
+}
+
+// Invalidate drops the cached token, so that the next call to Token fetches a new one.
+func (provider *CachingTokenProvider) Invalidate() {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+	provider.token = ""
+	provider.expiresAt = time.Time{}
+}
+
+// tokenSource holds the token provider of a request client. It is shared by the copies of the
+// request client held by the managers, so that swapping the provider applies to all of them.
+type tokenSource struct {
+	mutex    sync.RWMutex
+	provider TokenProvider
+}
+
+func (source *tokenSource) get() TokenProvider {
+	if source == nil {
+		return nil
+	}
+	source.mutex.RLock()
+	defer source.mutex.RUnlock()
+	return source.provider
+}
+
+func (source *tokenSource) set(provider TokenProvider) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	source.provider = provider
+}
+
+// WithTokenProvider makes the request client get the access token of every request from the given provider,
+// instead of using the static token it was created with.
+func WithTokenProvider(provider TokenProvider) RequestClientOption {
+	return func(options *requestClientOptions) {
+		options.tokenProvider = provider
+	}
+}
+
+// WithAppSecretProof makes the request client sign every request with an appsecret_proof,
+// the HMAC-SHA256 of the access token keyed with the app secret, as required by apps with
+// "Require App Secret" enabled. See https://developers.facebook.com/docs/graph-api/securing-requests.
+func WithAppSecretProof(appSecret string) RequestClientOption {
+	return func(options *requestClientOptions) {
+		options.appSecret = appSecret
+	}
+}
+
+// TokenProvider returns the provider of the access tokens of the requests.
+func (client *RequestClient) TokenProvider() TokenProvider {
+	return client.tokens.get()
+}
+
+// SetTokenProvider swaps the provider of the access tokens at runtime. The change applies to every
+// manager sharing this request client, use CloneWithTokenProvider to change the token of a single one.
+func (client *RequestClient) SetTokenProvider(provider TokenProvider) {
+	if client.tokens == nil {
+		client.tokens = &tokenSource{}
+	}
+	client.tokens.set(provider)
+}
+
+// SetAccessToken swaps the access token at runtime, it is a shorthand for SetTokenProvider with a StaticTokenProvider.
+func (client *RequestClient) SetAccessToken(token string) {
+	client.SetTokenProvider(StaticTokenProvider(token))
+}
+
+// CloneWithTokenProvider returns a copy of the request client which gets its tokens from the given provider,
+// leaving the token of this request client untouched.
+func (client *RequestClient) CloneWithTokenProvider(provider TokenProvider) *RequestClient {
+	clone := *client
+	clone.tokens = &tokenSource{provider: provider}
+	return &clone
+}
+
+// token returns the access token to send a request with.
+func (client *RequestClient) token(ctx context.Context) (string, error) {
+	provider := client.tokens.get()
+	if provider == nil {
+		return "", nil
+	}
+	token, err := provider.Token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error getting access token: %w", err)
+	}
+	return token, nil
+}
+
+// invalidateToken drops the cached token when err reports it as expired, and reports whether it did.
+func (client *RequestClient) invalidateToken(err error) bool {
+	graphApiError, ok := AsGraphAPIError(err)
+	if !ok || !graphApiError.IsAuthExpired() {
+		return false
+	}
+	invalidator, ok := client.tokens.get().(TokenInvalidator)
+	if !ok {
+		return false
+	}
+	invalidator.Invalidate()
+	return true
+}
+
+// appSecretProof returns the appsecret_proof of the given access token.
+func appSecretProof(appSecret, token string) string {
+	mac := hmac.New(sha256.New, []byte(appSecret))
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -6,5 +6,8 @@ type WhatsAppBusinessApiPaginationMeta struct {
 			Before string `json:"before,omitempty"`
 			After  string `json:"after,omitempty"`
 		} `json:"cursors,omitempty"`
+		// Next and Previous are the urls of the adjacent pages, they are left out on the first and last pages.
+		Next     string `json:"next,omitempty"`
+		Previous string `json:"previous,omitempty"`
 	} `json:"paging,omitempty"`
 }
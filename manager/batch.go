@@ -0,0 +1,58 @@
+package manager
+
+import (
+	"context"
+
+	"github.com/gTahidi/wapi.go/internal/request_client"
+)
+
+// Batch queues Graph API requests to send them in a single batch request of at most MaxBatchSize operations,
+// see https://developers.facebook.com/docs/graph-api/batch-requests. An operation can depend on a named one
+// and reference its result:
+//
+//	batch := client.NewBatch()
+//	batch.Add(createRequest).SetName("product")
+//	batch.Add(client.NewApiRequest("{result=product:$.id}", http.MethodGet)).SetDependsOn("product")
+//	responses, err := batch.Execute(ctx)
+//
+// The responses are in the order of the operations, the one of an operation which was not executed because
+// an operation it depends on failed has ErrBatchOperationSkipped as its error.
+type Batch = request_client.Batch
+
+// BatchOperation is a request queued in a Batch.
+type BatchOperation = request_client.BatchOperation
+
+// BatchResponse is the response to a single operation of a Batch, use its Decode method to read its body.
+type BatchResponse = request_client.BatchResponse
+
+// ApiRequest is a request to the Graph API, which can be executed on its own or added to a Batch.
+type ApiRequest = request_client.ApiRequest
+
+// MaxBatchSize is the maximum number of operations the Graph API accepts in a single batch request.
+const MaxBatchSize = request_client.MaxBatchSize
+
+// ErrBatchOperationSkipped is the error of a batch operation which got no response, usually
+// because an operation it depends on failed.
+var ErrBatchOperationSkipped = request_client.ErrBatchOperationSkipped
+
+// executeBatched sends the requests in batch requests of at most request_client.MaxBatchSize operations and
+// returns their responses in order. When a batch request fails as a whole, its error is set on all of its operations.
+func executeBatched(ctx context.Context, requester *request_client.RequestClient, requests []*request_client.ApiRequest) []request_client.BatchResponse {
+	responses := make([]request_client.BatchResponse, 0, len(requests))
+	for start := 0; start < len(requests); start += request_client.MaxBatchSize {
+		end := min(start+request_client.MaxBatchSize, len(requests))
+		batch := requester.NewBatch()
+		for _, request := range requests[start:end] {
+			batch.Add(request)
+		}
+		batchResponses, err := batch.Execute(ctx)
+		if err != nil {
+			for range requests[start:end] {
+				responses = append(responses, request_client.BatchResponse{Err: err})
+			}
+			continue
+		}
+		responses = append(responses, batchResponses...)
+	}
+	return responses
+}
@@ -2,9 +2,11 @@ package manager
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
@@ -18,20 +20,39 @@ import (
 type CatalogManager struct {
 	requester         *request_client.RequestClient
 	businessAccountId string
+	logger            *slog.Logger
 }
 
 type CatalogManagerConfig struct {
 	BusinessAccountId string
 	Requester         *request_client.RequestClient
+	// Logger is used to report the changes made to the catalogs, the logger of the requester is used when it is nil.
+	Logger *slog.Logger
 }
 
 func NewCatalogManager(config *CatalogManagerConfig) *CatalogManager {
 	return &CatalogManager{
 		requester:         config.Requester,
 		businessAccountId: config.BusinessAccountId,
+		logger:            managerLogger(config.Logger, config.Requester),
 	}
 }
 
+// SetAccessToken makes the manager send its requests with the given access token, see TokenProvider.
+func (cm *CatalogManager) SetAccessToken(token string) {
+	cm.SetTokenProvider(StaticTokenProvider(token))
+}
+
+// SetTokenProvider makes the manager get the access tokens of its requests from the given provider, see TokenProvider.
+func (cm *CatalogManager) SetTokenProvider(provider TokenProvider) {
+	cm.requester = cm.requester.CloneWithTokenProvider(provider)
+}
+
+// SetLogger sets the logger used to report the changes made to the catalogs, the logger of the requester by default.
+func (cm *CatalogManager) SetLogger(logger *slog.Logger) {
+	cm.logger = request_client.LoggerOrDiscard(logger)
+}
+
 // New helper type for key/value pairs.
 type KeyValue struct {
 	Key   string `json:"key"`
@@ -270,136 +291,137 @@ type CatalogFetchResponseEdge struct {
 // Note: This is not the same as listing all catalogs OWNED by the business.
 // For owned catalogs, use ListOwnedCatalogs.
 func (cm *CatalogManager) GetAllCatalogs() (*CatalogFetchResponseEdge, error) {
-	apiPath := strings.Join([]string{cm.businessAccountId, "product_catalogs"}, "/")
-	apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodGet)
-
-	fields := []string{
-		"id",
-		"name",
-		"vertical",
-		"product_count",
-		"data_sources",
-		"product_groups",
-		"product_sets",
-		"products",
-	}
-
-	for _, field := range fields {
-		apiRequest.AddField(request_client.ApiRequestQueryParamField{
-			Name:    field,
-			Filters: map[string]string{},
-		})
-	}
-
-	response, err := apiRequest.Execute()
+	return cm.GetAllCatalogsContext(context.Background())
+}
 
+// GetAllCatalogsContext is like GetAllCatalogs but uses ctx for the requests made to the Graph API.
+func (cm *CatalogManager) GetAllCatalogsContext(ctx context.Context) (*CatalogFetchResponseEdge, error) {
+	catalogs, err := cm.PaginateCatalogs(PaginationOptions{}).Collect(ctx)
 	if err != nil {
-		fmt.Println("GetAllCatalogs error", err)
-		return nil, err
-	}
-	var result CatalogFetchResponseEdge
-	if err := json.Unmarshal([]byte(response), &result); err != nil {
 		return nil, err
 	}
-	return &result, nil
+	return &CatalogFetchResponseEdge{Data: catalogs}, nil
+}
+
+// PaginateCatalogs returns a paginator over the catalogs linked to this WhatsApp Business Account.
+func (cm *CatalogManager) PaginateCatalogs(options PaginationOptions) *Paginator[Catalog] {
+	return newPaginator[Catalog](func() *request_client.ApiRequest {
+		apiPath := strings.Join([]string{cm.businessAccountId, "product_catalogs"}, "/")
+		apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodGet)
+
+		fields := []string{
+			"id",
+			"name",
+			"vertical",
+			"product_count",
+			"data_sources",
+			"product_groups",
+			"product_sets",
+			"products",
+		}
+
+		for _, field := range fields {
+			apiRequest.AddField(request_client.ApiRequestQueryParamField{
+				Name:    field,
+				Filters: map[string]string{},
+			})
+		}
+		return apiRequest
+	}, options)
 }
 
 // GetCatalogProducts retrieves the list of products for a given catalog.
 func (cm *CatalogManager) GetCatalogProducts(catalogId string) ([]ProductItem, error) {
-	apiPath := strings.Join([]string{catalogId, "products"}, "/")
-	apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodGet)
+	return cm.GetCatalogProductsContext(context.Background(), catalogId)
+}
 
-	fields := []string{
-		"id",
-		"price",
-		"additional_image_cdn_urls",
-		"additional_image_urls",
-		"condition",
-		"additional_variant_attributes",
-		"age_group",
-		"availability",
-		"brand",
-		"category",
-		"category_specific_fields",
-		"color",
-		"currency",
-		"custom_data",
-		"custom_label_0",
-		"custom_label_1",
-		"custom_label_2",
-		"custom_label_3",
-		"custom_label_4",
-		"custom_number_0",
-		"custom_number_1",
-		"custom_number_2",
-		"custom_number_3",
-		"custom_number_4",
-		"description",
-		"errors",
-		"expiration_date",
-		"fb_product_category",
-		"gender",
-		"gtin",
-		"image_cdn_urls",
-		"image_fetch_status",
-		"image_url",
-		"images",
-		"inventory",
-		"material",
-		"name",
-		"ordering_index",
-		"origin_country",
-		"parent_product_id",
-		"pattern",
-		"product_local_info",
-		"product_type",
-		"quantity_to_sell_on_facebook",
-		"retailer_id",
-		"retailer_product_group_id",
-		"sale_price",
-		"sale_price_end_date",
-		"sale_price_start_date",
-		"short_description",
-		"size",
-		"start_date",
-		"tags",
-		"url",
-		"vendor_id",
-		"visibility",
-		"wa_compliance_category",
-	}
-
-	for _, field := range fields {
-		apiRequest.AddField(request_client.ApiRequestQueryParamField{
-			Name:    field,
-			Filters: map[string]string{},
-		})
-	}
-
-	// ! TODO: proper pagination must be implemented here
-	apiRequest.AddQueryParam("limit", "1000")
-
-	response, err := apiRequest.Execute()
+// GetCatalogProductsContext is like GetCatalogProducts but uses ctx for the requests made to the Graph API.
+func (cm *CatalogManager) GetCatalogProductsContext(ctx context.Context, catalogId string) ([]ProductItem, error) {
+	products, err := cm.PaginateCatalogProducts(catalogId, PaginationOptions{PageSize: 1000}).Collect(ctx)
 	if err != nil {
 		return nil, err
 	}
+	return products, nil
+}
 
-	// Temporary response structure.
-	var res struct {
-		Data    []ProductItem   `json:"data"`
-		Paging  json.RawMessage `json:"paging"` // using RawMessage to ignore paging details
-		Summary struct {
-			TotalCount int `json:"total_count"`
-		} `json:"summary"`
-	}
-	if err := json.Unmarshal([]byte(response), &res); err != nil {
-		return nil, err
-	}
-	return res.Data, nil
+// PaginateCatalogProducts returns a paginator over the products of a given catalog.
+func (cm *CatalogManager) PaginateCatalogProducts(catalogId string, options PaginationOptions) *Paginator[ProductItem] {
+	return newPaginator[ProductItem](func() *request_client.ApiRequest {
+		apiPath := strings.Join([]string{catalogId, "products"}, "/")
+		apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodGet)
+
+		fields := []string{
+			"id",
+			"price",
+			"additional_image_cdn_urls",
+			"additional_image_urls",
+			"condition",
+			"additional_variant_attributes",
+			"age_group",
+			"availability",
+			"brand",
+			"category",
+			"category_specific_fields",
+			"color",
+			"currency",
+			"custom_data",
+			"custom_label_0",
+			"custom_label_1",
+			"custom_label_2",
+			"custom_label_3",
+			"custom_label_4",
+			"custom_number_0",
+			"custom_number_1",
+			"custom_number_2",
+			"custom_number_3",
+			"custom_number_4",
+			"description",
+			"errors",
+			"expiration_date",
+			"fb_product_category",
+			"gender",
+			"gtin",
+			"image_cdn_urls",
+			"image_fetch_status",
+			"image_url",
+			"images",
+			"inventory",
+			"material",
+			"name",
+			"ordering_index",
+			"origin_country",
+			"parent_product_id",
+			"pattern",
+			"product_local_info",
+			"product_type",
+			"quantity_to_sell_on_facebook",
+			"retailer_id",
+			"retailer_product_group_id",
+			"sale_price",
+			"sale_price_end_date",
+			"sale_price_start_date",
+			"short_description",
+			"size",
+			"start_date",
+			"tags",
+			"url",
+			"vendor_id",
+			"visibility",
+			"wa_compliance_category",
+		}
+
+		for _, field := range fields {
+			apiRequest.AddField(request_client.ApiRequestQueryParamField{
+				Name:    field,
+				Filters: map[string]string{},
+			})
+		}
+		return apiRequest
+	}, options)
 }
 
 type CreateProductCatalogOptions struct {
-    Success string `json:"success,omitempty"`
+	Success string `json:"success,omitempty"`
 }
 
 // Deprecated: CreateNewProductCatalog misused the Graph API by calling
@@ -411,9 +433,9 @@ type CreateProductCatalogOptions struct {
 // (POST /{business_account_id}/owned_product_catalogs) and
 // AssociateCatalog to link an existing catalog to this business account.
 func (cm *CatalogManager) CreateNewProductCatalog() (CreateProductCatalogOptions, error) {
-    return CreateProductCatalogOptions{}, fmt.Errorf(
-        "CreateNewProductCatalog is deprecated: use CreateCatalog to create or AssociateCatalog to link an existing catalog",
-    )
+	return CreateProductCatalogOptions{}, fmt.Errorf(
+		"CreateNewProductCatalog is deprecated: use CreateCatalog to create or AssociateCatalog to link an existing catalog",
+	)
 }
 
 // AssociateCatalog associates an existing product catalog to this business account (WABA).
@@ -421,57 +443,74 @@ func (cm *CatalogManager) CreateNewProductCatalog() (CreateProductCatalogOptions
 // Body: { "catalog_id": "<catalog_id>" }
 // Returns true on success.
 func (cm *CatalogManager) AssociateCatalog(catalogId string) (bool, error) {
-    if strings.TrimSpace(catalogId) == "" {
-        return false, fmt.Errorf("catalogId is required to associate a catalog")
-    }
-
-    apiPath := strings.Join([]string{cm.businessAccountId, "product_catalogs"}, "/")
-    apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodPost)
-
-    body := map[string]string{
-        "catalog_id": catalogId,
-    }
-    payload, err := json.Marshal(body)
-    if err != nil {
-        return false, fmt.Errorf("failed to marshal association body: %w", err)
-    }
-    apiRequest.SetBody(string(payload))
-
-    response, err := apiRequest.Execute()
-    if err != nil {
-        return false, err
-    }
-    var res struct {
-        Success bool `json:"success"`
-    }
-    if err := json.Unmarshal([]byte(response), &res); err != nil {
-        return false, fmt.Errorf("failed to parse association response: %w", err)
-    }
-    if !res.Success {
-        return false, fmt.Errorf("catalog association failed")
-    }
-    return true, nil
+	return cm.AssociateCatalogContext(context.Background(), catalogId)
 }
 
-// ListProductFeeds lists product feeds for a given catalog.
-func (cm *CatalogManager) ListProductFeeds(catalogId string) ([]ProductFeed, error) {
-	apiPath := strings.Join([]string{catalogId, "product_feeds"}, "/")
-	apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodGet)
-	response, err := apiRequest.Execute()
+// AssociateCatalogContext is like AssociateCatalog but uses ctx for the requests made to the Graph API.
+func (cm *CatalogManager) AssociateCatalogContext(ctx context.Context, catalogId string) (bool, error) {
+	if strings.TrimSpace(catalogId) == "" {
+		return false, fmt.Errorf("catalogId is required to associate a catalog")
+	}
+
+	apiPath := strings.Join([]string{cm.businessAccountId, "product_catalogs"}, "/")
+	apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodPost)
+
+	body := map[string]string{
+		"catalog_id": catalogId,
+	}
+	payload, err := json.Marshal(body)
 	if err != nil {
-		return nil, err
+		return false, fmt.Errorf("failed to marshal association body: %w", err)
+	}
+	apiRequest.SetBody(string(payload))
+
+	response, err := apiRequest.Execute(ctx)
+	if err != nil {
+		return false, err
 	}
 	var res struct {
-		Data []ProductFeed `json:"data"`
+		Success bool `json:"success"`
 	}
 	if err := json.Unmarshal([]byte(response), &res); err != nil {
+		return false, fmt.Errorf("failed to parse association response: %w", err)
+	}
+	if !res.Success {
+		return false, fmt.Errorf("catalog association failed")
+	}
+	cm.logger.DebugContext(ctx, "catalog associated", "catalog_id", catalogId)
+	return true, nil
+}
+
+// ListProductFeeds lists product feeds for a given catalog.
+func (cm *CatalogManager) ListProductFeeds(catalogId string) ([]ProductFeed, error) {
+	return cm.ListProductFeedsContext(context.Background(), catalogId)
+}
+
+// ListProductFeedsContext is like ListProductFeeds but uses ctx for the requests made to the Graph API.
+func (cm *CatalogManager) ListProductFeedsContext(ctx context.Context, catalogId string) ([]ProductFeed, error) {
+	feeds, err := cm.PaginateProductFeeds(catalogId, PaginationOptions{}).Collect(ctx)
+	if err != nil {
 		return nil, err
 	}
-	return res.Data, nil
+	return feeds, nil
+}
+
+// PaginateProductFeeds returns a paginator over the product feeds of a given catalog.
+func (cm *CatalogManager) PaginateProductFeeds(catalogId string, options PaginationOptions) *Paginator[ProductFeed] {
+	return newPaginator[ProductFeed](func() *request_client.ApiRequest {
+		apiPath := strings.Join([]string{catalogId, "product_feeds"}, "/")
+		apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodGet)
+		return apiRequest
+	}, options)
 }
 
 // UploadFeedCSV uploads a CSV file to a product feed using multipart/form-data.
 func (cm *CatalogManager) UploadFeedCSV(feedId string, file io.Reader, filename, mimeType string, updateOnly bool) (*FeedUploadResponse, error) {
+	return cm.UploadFeedCSVContext(context.Background(), feedId, file, filename, mimeType, updateOnly)
+}
+
+// UploadFeedCSVContext is like UploadFeedCSV but uses ctx for the requests made to the Graph API.
+func (cm *CatalogManager) UploadFeedCSVContext(ctx context.Context, feedId string, file io.Reader, filename, mimeType string, updateOnly bool) (*FeedUploadResponse, error) {
 	// Prepare multipart body with update_only and a single file part
 	bodyBuf := new(bytes.Buffer)
 	writer := multipart.NewWriter(bodyBuf)
@@ -501,7 +540,7 @@ func (cm *CatalogManager) UploadFeedCSV(feedId string, file io.Reader, filename,
 
 	apiPath := strings.Join([]string{feedId, "uploads"}, "/")
 	contentType := writer.FormDataContentType()
-	responseBody, err := cm.requester.RequestMultipart(http.MethodPost, apiPath, bodyBuf, contentType)
+	responseBody, err := cm.requester.RequestMultipart(ctx, http.MethodPost, apiPath, bodyBuf, contentType)
 	if err != nil {
 		return nil, fmt.Errorf("error uploading CSV: %w", err)
 	}
@@ -510,10 +549,16 @@ func (cm *CatalogManager) UploadFeedCSV(feedId string, file io.Reader, filename,
 	if err := json.Unmarshal([]byte(responseBody), &res); err != nil {
 		return nil, fmt.Errorf("failed to parse upload response: %w", err)
 	}
+	cm.logger.DebugContext(ctx, "feed CSV uploaded", "feed_id", feedId, "upload_id", res.Id)
 	return &res, nil
 }
 
 func (cm *CatalogManager) UploadFeedCSVFromURL(feedId, csvURL string, updateOnly bool) (*FeedUploadResponse, error) {
+	return cm.UploadFeedCSVFromURLContext(context.Background(), feedId, csvURL, updateOnly)
+}
+
+// UploadFeedCSVFromURLContext is like UploadFeedCSVFromURL but uses ctx for the requests made to the Graph API.
+func (cm *CatalogManager) UploadFeedCSVFromURLContext(ctx context.Context, feedId, csvURL string, updateOnly bool) (*FeedUploadResponse, error) {
 	apiPath := strings.Join([]string{feedId, "uploads"}, "/")
 	apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodPost)
 	body := map[string]interface{}{
@@ -526,7 +571,7 @@ func (cm *CatalogManager) UploadFeedCSVFromURL(feedId, csvURL string, updateOnly
 		return nil, fmt.Errorf("failed to marshal hosted feed body: %w", err)
 	}
 	apiRequest.SetBody(string(payload))
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -534,30 +579,42 @@ func (cm *CatalogManager) UploadFeedCSVFromURL(feedId, csvURL string, updateOnly
 	if err := json.Unmarshal([]byte(response), &res); err != nil {
 		return nil, err
 	}
+	cm.logger.DebugContext(ctx, "feed CSV uploaded", "feed_id", feedId, "upload_id", res.Id)
 	return &res, nil
 }
 
 func (cm *CatalogManager) ListFeedUploads(feedId string) ([]FeedUploadSession, error) {
-	apiPath := strings.Join([]string{feedId, "uploads"}, "/")
-	apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodGet)
-	response, err := apiRequest.Execute()
+	return cm.ListFeedUploadsContext(context.Background(), feedId)
+}
+
+// ListFeedUploadsContext is like ListFeedUploads but uses ctx for the requests made to the Graph API.
+func (cm *CatalogManager) ListFeedUploadsContext(ctx context.Context, feedId string) ([]FeedUploadSession, error) {
+	uploads, err := cm.PaginateFeedUploads(feedId, PaginationOptions{}).Collect(ctx)
 	if err != nil {
 		return nil, err
 	}
-	var res struct {
-		Data []FeedUploadSession `json:"data"`
-	}
-	if err := json.Unmarshal([]byte(response), &res); err != nil {
-		return nil, err
-	}
-	return res.Data, nil
+	return uploads, nil
+}
+
+// PaginateFeedUploads returns a paginator over the upload sessions of a given product feed.
+func (cm *CatalogManager) PaginateFeedUploads(feedId string, options PaginationOptions) *Paginator[FeedUploadSession] {
+	return newPaginator[FeedUploadSession](func() *request_client.ApiRequest {
+		apiPath := strings.Join([]string{feedId, "uploads"}, "/")
+		apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodGet)
+		return apiRequest
+	}, options)
 }
 
 func (cm *CatalogManager) GetFeedUploadStatus(uploadId string) (*FeedUploadErrorReportResponse, error) {
+	return cm.GetFeedUploadStatusContext(context.Background(), uploadId)
+}
+
+// GetFeedUploadStatusContext is like GetFeedUploadStatus but uses ctx for the requests made to the Graph API.
+func (cm *CatalogManager) GetFeedUploadStatusContext(ctx context.Context, uploadId string) (*FeedUploadErrorReportResponse, error) {
 	apiRequest := cm.requester.NewApiRequest(uploadId, http.MethodGet)
 	// include error_report field for convenience
 	apiRequest.AddField(request_client.ApiRequestQueryParamField{Name: "error_report", Filters: map[string]string{}})
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -569,25 +626,36 @@ func (cm *CatalogManager) GetFeedUploadStatus(uploadId string) (*FeedUploadError
 }
 
 func (cm *CatalogManager) GetFeedUploadErrors(uploadId string) ([]FeedUploadError, error) {
-	apiPath := strings.Join([]string{uploadId, "errors"}, "/")
-	apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodGet)
-	response, err := apiRequest.Execute()
+	return cm.GetFeedUploadErrorsContext(context.Background(), uploadId)
+}
+
+// GetFeedUploadErrorsContext is like GetFeedUploadErrors but uses ctx for the requests made to the Graph API.
+func (cm *CatalogManager) GetFeedUploadErrorsContext(ctx context.Context, uploadId string) ([]FeedUploadError, error) {
+	uploadErrors, err := cm.PaginateFeedUploadErrors(uploadId, PaginationOptions{}).Collect(ctx)
 	if err != nil {
 		return nil, err
 	}
-	var res struct {
-		Data []FeedUploadError `json:"data"`
-	}
-	if err := json.Unmarshal([]byte(response), &res); err != nil {
-		return nil, err
-	}
-	return res.Data, nil
+	return uploadErrors, nil
+}
+
+// PaginateFeedUploadErrors returns a paginator over the errors of a given feed upload session.
+func (cm *CatalogManager) PaginateFeedUploadErrors(uploadId string, options PaginationOptions) *Paginator[FeedUploadError] {
+	return newPaginator[FeedUploadError](func() *request_client.ApiRequest {
+		apiPath := strings.Join([]string{uploadId, "errors"}, "/")
+		apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodGet)
+		return apiRequest
+	}, options)
 }
 
 func (cm *CatalogManager) RequestFeedUploadErrorReport(uploadId string) (bool, error) {
+	return cm.RequestFeedUploadErrorReportContext(context.Background(), uploadId)
+}
+
+// RequestFeedUploadErrorReportContext is like RequestFeedUploadErrorReport but uses ctx for the requests made to the Graph API.
+func (cm *CatalogManager) RequestFeedUploadErrorReportContext(ctx context.Context, uploadId string) (bool, error) {
 	apiPath := strings.Join([]string{uploadId, "error_report"}, "/")
 	apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodPost)
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return false, err
 	}
@@ -601,9 +669,14 @@ func (cm *CatalogManager) RequestFeedUploadErrorReport(uploadId string) (bool, e
 }
 
 func (cm *CatalogManager) GetFeedUploadErrorReport(uploadId string) (*FeedUploadErrorReportResponse, error) {
+	return cm.GetFeedUploadErrorReportContext(context.Background(), uploadId)
+}
+
+// GetFeedUploadErrorReportContext is like GetFeedUploadErrorReport but uses ctx for the requests made to the Graph API.
+func (cm *CatalogManager) GetFeedUploadErrorReportContext(ctx context.Context, uploadId string) (*FeedUploadErrorReportResponse, error) {
 	apiRequest := cm.requester.NewApiRequest(uploadId, http.MethodGet)
 	apiRequest.AddField(request_client.ApiRequestQueryParamField{Name: "error_report", Filters: map[string]string{}})
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -627,6 +700,19 @@ func (cm *CatalogManager) CreateScheduledProductFeed(
 	updateOnly bool,
 	ingestionSourceType string,
 	primaryFeedIds []string,
+) (*ProductFeed, error) {
+	return cm.CreateScheduledProductFeedContext(context.Background(), catalogId, name, schedule, updateOnly, ingestionSourceType, primaryFeedIds)
+}
+
+// CreateScheduledProductFeedContext is like CreateScheduledProductFeed but uses ctx for the requests made to the Graph API.
+func (cm *CatalogManager) CreateScheduledProductFeedContext(
+	ctx context.Context,
+	catalogId string,
+	name string,
+	schedule ProductFeedSchedule,
+	updateOnly bool,
+	ingestionSourceType string,
+	primaryFeedIds []string,
 ) (*ProductFeed, error) {
 	apiPath := strings.Join([]string{catalogId, "product_feeds"}, "/")
 	apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodPost)
@@ -646,7 +732,7 @@ func (cm *CatalogManager) CreateScheduledProductFeed(
 		return nil, fmt.Errorf("failed to marshal scheduled feed body: %w", err)
 	}
 	apiRequest.SetBody(string(payload))
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -664,6 +750,7 @@ func (cm *CatalogManager) CreateScheduledProductFeed(
 	if apiResp.ID == "" {
 		return nil, fmt.Errorf("feed created but no ID returned from Meta API")
 	}
+	cm.logger.DebugContext(ctx, "product feed created", "catalog_id", catalogId, "feed_id", apiResp.ID)
 
 	// Return a ProductFeed with the ID from Meta and the name we sent
 	return &ProductFeed{
@@ -676,49 +763,57 @@ func (cm *CatalogManager) CreateScheduledProductFeed(
 // CreateProductFeed creates a product feed without a schedule (for immediate CSV uploads).
 // Use UploadFeedCSV or UploadFeedCSVFromURL afterwards to ingest data.
 func (cm *CatalogManager) CreateProductFeed(catalogId string, name string) (*ProductFeed, error) {
-    apiPath := strings.Join([]string{catalogId, "product_feeds"}, "/")
-    apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodPost)
-    body := map[string]interface{}{
-        "name": name,
-    }
-    payload, err := json.Marshal(body)
-    if err != nil {
-        return nil, fmt.Errorf("failed to marshal feed body: %w", err)
-    }
-    apiRequest.SetBody(string(payload))
-    response, err := apiRequest.Execute()
-    if err != nil {
-        return nil, err
-    }
-
-    // Debug: Log the raw response from Meta's API
-    fmt.Printf("[DEBUG] CreateProductFeed - Raw Meta API response: %s\n", response)
-
-    // Meta's API returns a minimal response: {"id": "feed_id"}
-    // Parse this simple format first
-    var apiResp struct {
-        ID string `json:"id"`
-    }
-    if err := json.Unmarshal([]byte(response), &apiResp); err != nil {
-        return nil, fmt.Errorf("failed to parse feed creation response (raw: %s): %w", response, err)
-    }
-
-    // Validate we got an ID
-    if apiResp.ID == "" {
-        return nil, fmt.Errorf("feed created but no ID returned from Meta API (raw response: %s)", response)
-    }
-
-    // Return a ProductFeed with the ID from Meta and the name we sent
-    return &ProductFeed{
-        Id:   apiResp.ID,
-        Name: name,
-        // FileName is not returned on creation, only when listing feeds
-    }, nil
+	return cm.CreateProductFeedContext(context.Background(), catalogId, name)
+}
+
+// CreateProductFeedContext is like CreateProductFeed but uses ctx for the requests made to the Graph API.
+func (cm *CatalogManager) CreateProductFeedContext(ctx context.Context, catalogId string, name string) (*ProductFeed, error) {
+	apiPath := strings.Join([]string{catalogId, "product_feeds"}, "/")
+	apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodPost)
+	body := map[string]interface{}{
+		"name": name,
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal feed body: %w", err)
+	}
+	apiRequest.SetBody(string(payload))
+	response, err := apiRequest.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Meta's API returns a minimal response: {"id": "feed_id"}
+	// Parse this simple format first
+	var apiResp struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(response), &apiResp); err != nil {
+		return nil, fmt.Errorf("failed to parse feed creation response (raw: %s): %w", response, err)
+	}
+
+	// Validate we got an ID
+	if apiResp.ID == "" {
+		return nil, fmt.Errorf("feed created but no ID returned from Meta API (raw response: %s)", response)
+	}
+	cm.logger.DebugContext(ctx, "product feed created", "catalog_id", catalogId, "feed_id", apiResp.ID)
+
+	// Return a ProductFeed with the ID from Meta and the name we sent
+	return &ProductFeed{
+		Id:   apiResp.ID,
+		Name: name,
+		// FileName is not returned on creation, only when listing feeds
+	}, nil
 }
 
 // UpsertProductItem updates or creates a product item using Meta's format.
 // fields should include at least retailer_id, name, price, currency, image_url, availability, etc.
 func (cm *CatalogManager) UpsertProductItem(catalogId string, fields map[string]interface{}) (*ProductItem, error) {
+	return cm.UpsertProductItemContext(context.Background(), catalogId, fields)
+}
+
+// UpsertProductItemContext is like UpsertProductItem but uses ctx for the requests made to the Graph API.
+func (cm *CatalogManager) UpsertProductItemContext(ctx context.Context, catalogId string, fields map[string]interface{}) (*ProductItem, error) {
 	apiPath := strings.Join([]string{catalogId, "products"}, "/")
 	apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodPost)
 	payload, err := json.Marshal(fields)
@@ -726,7 +821,7 @@ func (cm *CatalogManager) UpsertProductItem(catalogId string, fields map[string]
 		return nil, fmt.Errorf("failed to marshall product fields: %w", err)
 	}
 	apiRequest.SetBody(string(payload))
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -734,33 +829,58 @@ func (cm *CatalogManager) UpsertProductItem(catalogId string, fields map[string]
 	if err := json.Unmarshal([]byte(response), &res); err != nil {
 		return nil, err
 	}
+	cm.logger.DebugContext(ctx, "product item upserted", "catalog_id", catalogId, "product_id", res.Id)
 	return &res, nil
 }
 
-// BatchUpsertProductItems performs multiple upserts sequentially.
+// BatchUpsertProductItems performs multiple upserts in Graph API batch requests of up to 50 items.
 // Returns the successfully upserted items and a map of index->error for failures.
 func (cm *CatalogManager) BatchUpsertProductItems(catalogId string, items []map[string]interface{}) ([]ProductItem, map[int]error) {
+	return cm.BatchUpsertProductItemsContext(context.Background(), catalogId, items)
+}
+
+// BatchUpsertProductItemsContext is like BatchUpsertProductItems but uses ctx for the requests made to the Graph API.
+func (cm *CatalogManager) BatchUpsertProductItemsContext(ctx context.Context, catalogId string, items []map[string]interface{}) ([]ProductItem, map[int]error) {
 	var results []ProductItem
 	errs := make(map[int]error)
+	apiPath := strings.Join([]string{catalogId, "products"}, "/")
+	requests := make([]*request_client.ApiRequest, 0, len(items))
+	indexes := make([]int, 0, len(items))
 	for i, fields := range items {
-		item, err := cm.UpsertProductItem(catalogId, fields)
+		payload, err := json.Marshal(fields)
 		if err != nil {
-			errs[i] = err
+			errs[i] = fmt.Errorf("failed to marshall product fields: %w", err)
 			continue
 		}
-		results = append(results, *item)
+		apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodPost)
+		apiRequest.SetBody(string(payload))
+		requests = append(requests, apiRequest)
+		indexes = append(indexes, i)
+	}
+	for i, response := range executeBatched(ctx, cm.requester, requests) {
+		var item ProductItem
+		if err := response.Decode(&item); err != nil {
+			errs[indexes[i]] = err
+			continue
+		}
+		results = append(results, item)
 	}
 	return results, errs
 }
 
 // UpdateProductImages updates image_url and additional_image_urls for a retailer_id.
 func (cm *CatalogManager) UpdateProductImages(catalogId, retailerId, imageURL string, additionalImageURLs []string) (*ProductItem, error) {
+	return cm.UpdateProductImagesContext(context.Background(), catalogId, retailerId, imageURL, additionalImageURLs)
+}
+
+// UpdateProductImagesContext is like UpdateProductImages but uses ctx for the requests made to the Graph API.
+func (cm *CatalogManager) UpdateProductImagesContext(ctx context.Context, catalogId, retailerId, imageURL string, additionalImageURLs []string) (*ProductItem, error) {
 	fields := map[string]interface{}{
 		"retailer_id":           retailerId,
 		"image_url":             imageURL,
 		"additional_image_urls": additionalImageURLs,
 	}
-	return cm.UpsertProductItem(catalogId, fields)
+	return cm.UpsertProductItemContext(ctx, catalogId, fields)
 }
 
 // CreateCatalog creates a new product catalog for the business account.
@@ -772,32 +892,34 @@ func (cm *CatalogManager) UpdateProductImages(catalogId, retailerId, imageURL st
 // in Business Manager before this endpoint can succeed.
 // Returns the created catalog with ID and name.
 func (cm *CatalogManager) CreateCatalog(name string, vertical string) (*Catalog, error) {
+	return cm.CreateCatalogContext(context.Background(), name, vertical)
+}
+
+// CreateCatalogContext is like CreateCatalog but uses ctx for the requests made to the Graph API.
+func (cm *CatalogManager) CreateCatalogContext(ctx context.Context, name string, vertical string) (*Catalog, error) {
 	if vertical == "" {
 		vertical = "commerce"
 	}
-	
+
 	apiPath := fmt.Sprintf("%s/owned_product_catalogs", cm.businessAccountId)
 	apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodPost)
-	
+
 	body := map[string]interface{}{
 		"name":     name,
 		"vertical": vertical,
 	}
-	
+
 	payload, err := json.Marshal(body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal catalog body: %w", err)
 	}
-	
+
 	apiRequest.SetBody(string(payload))
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return nil, err
 	}
-	
-	// Debug: Log the raw response from Meta's API
-	fmt.Printf("[DEBUG] CreateCatalog - Raw Meta API response: %s\n", response)
-	
+
 	// Meta returns {"id": "catalog_id"}
 	var apiResp struct {
 		ID string `json:"id"`
@@ -805,11 +927,12 @@ func (cm *CatalogManager) CreateCatalog(name string, vertical string) (*Catalog,
 	if err := json.Unmarshal([]byte(response), &apiResp); err != nil {
 		return nil, fmt.Errorf("failed to parse catalog creation response (raw: %s): %w", response, err)
 	}
-	
+
 	if apiResp.ID == "" {
 		return nil, fmt.Errorf("catalog created but no ID returned from Meta API (raw response: %s)", response)
 	}
-	
+	cm.logger.DebugContext(ctx, "catalog created", "catalog_id", apiResp.ID)
+
 	// Return catalog with ID and name
 	return &Catalog{
 		Id:       apiResp.ID,
@@ -821,66 +944,80 @@ func (cm *CatalogManager) CreateCatalog(name string, vertical string) (*Catalog,
 // GetCatalog retrieves a catalog by ID with optional fields.
 // fields: comma-separated list of fields to retrieve (e.g., "id,name,product_count,vertical")
 func (cm *CatalogManager) GetCatalog(catalogId string, fields string) (*Catalog, error) {
+	return cm.GetCatalogContext(context.Background(), catalogId, fields)
+}
+
+// GetCatalogContext is like GetCatalog but uses ctx for the requests made to the Graph API.
+func (cm *CatalogManager) GetCatalogContext(ctx context.Context, catalogId string, fields string) (*Catalog, error) {
 	apiPath := catalogId
 	if fields != "" {
 		apiPath = fmt.Sprintf("%s?fields=%s", catalogId, fields)
 	}
-	
+
 	apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodGet)
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return nil, err
 	}
-	
+
 	var catalog Catalog
 	if err := json.Unmarshal([]byte(response), &catalog); err != nil {
 		return nil, fmt.Errorf("failed to parse catalog response: %w", err)
 	}
-	
+
 	return &catalog, nil
 }
 
 // ListOwnedCatalogs retrieves all catalogs owned by the business account.
 // Returns a simplified list of catalogs with basic information.
 func (cm *CatalogManager) ListOwnedCatalogs() ([]Catalog, error) {
-	apiPath := fmt.Sprintf("%s/owned_product_catalogs?fields=id,name,product_count,vertical", cm.businessAccountId)
-	apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodGet)
-	
-	response, err := apiRequest.Execute()
+	return cm.ListOwnedCatalogsContext(context.Background())
+}
+
+// ListOwnedCatalogsContext is like ListOwnedCatalogs but uses ctx for the requests made to the Graph API.
+func (cm *CatalogManager) ListOwnedCatalogsContext(ctx context.Context) ([]Catalog, error) {
+	catalogs, err := cm.PaginateOwnedCatalogs(PaginationOptions{}).Collect(ctx)
 	if err != nil {
 		return nil, err
 	}
-	
-	var result struct {
-		Data []Catalog `json:"data"`
-	}
-	if err := json.Unmarshal([]byte(response), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse catalogs response: %w", err)
-	}
-	
-	return result.Data, nil
+	return catalogs, nil
+}
+
+// PaginateOwnedCatalogs returns a paginator over the catalogs owned by the business account.
+func (cm *CatalogManager) PaginateOwnedCatalogs(options PaginationOptions) *Paginator[Catalog] {
+	return newPaginator[Catalog](func() *request_client.ApiRequest {
+		apiPath := fmt.Sprintf("%s/owned_product_catalogs?fields=id,name,product_count,vertical", cm.businessAccountId)
+		apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodGet)
+		return apiRequest
+	}, options)
 }
 
 // UpdateCatalog updates a catalog's name.
 func (cm *CatalogManager) UpdateCatalog(catalogId string, name string) (*Catalog, error) {
+	return cm.UpdateCatalogContext(context.Background(), catalogId, name)
+}
+
+// UpdateCatalogContext is like UpdateCatalog but uses ctx for the requests made to the Graph API.
+func (cm *CatalogManager) UpdateCatalogContext(ctx context.Context, catalogId string, name string) (*Catalog, error) {
 	apiPath := catalogId
 	apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodPost)
-	
+	apiRequest.SetIdempotent(true)
+
 	body := map[string]interface{}{
 		"name": name,
 	}
-	
+
 	payload, err := json.Marshal(body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal catalog update body: %w", err)
 	}
-	
+
 	apiRequest.SetBody(string(payload))
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return nil, err
 	}
-	
+
 	// Meta returns {"success": true}
 	var apiResp struct {
 		Success bool `json:"success"`
@@ -888,35 +1025,42 @@ func (cm *CatalogManager) UpdateCatalog(catalogId string, name string) (*Catalog
 	if err := json.Unmarshal([]byte(response), &apiResp); err != nil {
 		return nil, fmt.Errorf("failed to parse catalog update response: %w", err)
 	}
-	
+
 	if !apiResp.Success {
 		return nil, fmt.Errorf("catalog update failed")
 	}
-	
+	cm.logger.DebugContext(ctx, "catalog updated", "catalog_id", catalogId)
+
 	// Return updated catalog
-	return cm.GetCatalog(catalogId, "id,name,product_count,vertical")
+	return cm.GetCatalogContext(ctx, catalogId, "id,name,product_count,vertical")
 }
 
 // DeleteCatalog deletes a catalog by ID.
 func (cm *CatalogManager) DeleteCatalog(catalogId string) error {
+	return cm.DeleteCatalogContext(context.Background(), catalogId)
+}
+
+// DeleteCatalogContext is like DeleteCatalog but uses ctx for the requests made to the Graph API.
+func (cm *CatalogManager) DeleteCatalogContext(ctx context.Context, catalogId string) error {
 	apiPath := catalogId
 	apiRequest := cm.requester.NewApiRequest(apiPath, http.MethodDelete)
-	
-	response, err := apiRequest.Execute()
+
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return err
 	}
-	
+
 	var apiResp struct {
 		Success bool `json:"success"`
 	}
 	if err := json.Unmarshal([]byte(response), &apiResp); err != nil {
 		return fmt.Errorf("failed to parse catalog deletion response: %w", err)
 	}
-	
+
 	if !apiResp.Success {
 		return fmt.Errorf("catalog deletion failed")
 	}
-	
+	cm.logger.DebugContext(ctx, "catalog deleted", "catalog_id", catalogId)
+
 	return nil
 }
@@ -0,0 +1,44 @@
+package manager
+
+import (
+	"github.com/gTahidi/wapi.go/internal/request_client"
+)
+
+// GraphAPIError is the error returned by the managers when the Graph API rejects a request.
+// It carries the http status, the error code and subcode, and the fbtrace_id to share with Meta support.
+//
+//	var graphApiError *manager.GraphAPIError
+//	if errors.As(err, &graphApiError) && graphApiError.IsReEngagementWindow() {
+//		// send a template message instead
+//	}
+type GraphAPIError = request_client.GraphAPIError
+
+// IsRateLimited reports whether err is a Graph API error caused by a rate or throughput limit.
+func IsRateLimited(err error) bool {
+	graphApiError, ok := request_client.AsGraphAPIError(err)
+	return ok && graphApiError.IsRateLimited()
+}
+
+// IsReEngagementWindow reports whether err is a Graph API error caused by the customer service window being closed.
+func IsReEngagementWindow(err error) bool {
+	graphApiError, ok := request_client.AsGraphAPIError(err)
+	return ok && graphApiError.IsReEngagementWindow()
+}
+
+// IsAuthExpired reports whether err is a Graph API error caused by an expired or invalid access token.
+func IsAuthExpired(err error) bool {
+	graphApiError, ok := request_client.AsGraphAPIError(err)
+	return ok && graphApiError.IsAuthExpired()
+}
+
+// IsRecipientInvalid reports whether err is a Graph API error caused by a recipient that can not receive the message.
+func IsRecipientInvalid(err error) bool {
+	graphApiError, ok := request_client.AsGraphAPIError(err)
+	return ok && graphApiError.IsRecipientInvalid()
+}
+
+// IsTransient reports whether err is a temporary Graph API failure, so the request may succeed later.
+func IsTransient(err error) bool {
+	graphApiError, ok := request_client.AsGraphAPIError(err)
+	return ok && graphApiError.IsTransient()
+}
@@ -1,8 +1,11 @@
 package manager
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gTahidi/wapi.go/pkg/events"
 )
@@ -11,11 +14,15 @@ import (
 type ChannelEvent struct {
 	Type events.EventType // Type is the type of the event.
 	Data events.BaseEvent // Data is the data associated with the event.
+	// tracked is set when the event is counted as pending until a handler registered with On is done with it.
+	tracked bool
 }
 
 // EventManager is responsible for managing events and their subscribers.
 type EventManager struct {
 	subscribers  map[events.EventType]chan ChannelEvent // subscribers is a map of event types to channels of ChannelEvent.
+	handled      map[events.EventType]bool              // handled is the set of event types with a handler registered with On.
+	pending      atomic.Int64                           // pending is the number of events queued for or being processed by a handler.
 	sync.RWMutex                                        // RWMutex is used to synchronize access to the subscribers map.
 }
 
@@ -23,6 +30,7 @@ type EventManager struct {
 func NewEventManager() *EventManager {
 	return &EventManager{
 		subscribers: make(map[events.EventType]chan ChannelEvent),
+		handled:     make(map[events.EventType]bool),
 	}
 }
 
@@ -43,6 +51,7 @@ func (em *EventManager) Unsubscribe(id events.EventType) {
 	em.Lock()
 	defer em.Unlock()
 	delete(em.subscribers, id)
+	delete(em.handled, id)
 }
 
 // Publish publishes an event to the event system and notifies all the subscribers.
@@ -51,12 +60,20 @@ func (em *EventManager) Publish(event events.EventType, data events.BaseEvent) e
 	defer em.Unlock()
 
 	if ch, ok := em.subscribers[event]; ok {
+		tracked := em.handled[event]
+		if tracked {
+			em.pending.Add(1)
+		}
 		select {
 		case ch <- ChannelEvent{
-			Type: event,
-			Data: data,
+			Type:    event,
+			Data:    data,
+			tracked: tracked,
 		}:
 		default:
+			if tracked {
+				em.pending.Add(-1)
+			}
 			return fmt.Errorf("event queue full for type: %s", event)
 		}
 	}
@@ -68,13 +85,34 @@ func (em *EventManager) Publish(event events.EventType, data events.BaseEvent) e
 // It returns the event type that the handler is registered for.
 func (em *EventManager) On(eventName events.EventType, handler func(events.BaseEvent)) events.EventType {
 	ch, _ := em.Subscribe(eventName)
+	em.Lock()
+	em.handled[eventName] = true
+	em.Unlock()
 	go func() {
 		for {
 			select {
 			case event := <-ch:
 				handler(event.Data)
+				if event.tracked {
+					em.pending.Add(-1)
+				}
 			}
 		}
 	}()
 	return eventName
 }
+
+// Drain waits until the handlers registered with On are done with every event published so far,
+// or until ctx is done. It is used to shut down without losing the events already received.
+func (em *EventManager) Drain(ctx context.Context) error {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for em.pending.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%d events still pending: %w", em.pending.Load(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
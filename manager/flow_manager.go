@@ -2,8 +2,10 @@ package manager
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"strings"
@@ -83,23 +85,42 @@ type FlowsListResponse struct {
 }
 type FlowManager struct {
 	businessAccountId string
-	apiAccessToken    string
 	requester         *request_client.RequestClient
+	logger            *slog.Logger
 }
 type FlowManagerConfig struct {
 	BusinessAccountId string
-	ApiAccessToken    string
-	Requester         *request_client.RequestClient
+	// Deprecated: ApiAccessToken is ignored, the requests are sent with the access token of Requester,
+	// use SetAccessToken to give the manager a token of its own.
+	ApiAccessToken string
+	Requester      *request_client.RequestClient
+	// Logger is used to report the changes made to the flows, the logger of the requester is used when it is nil.
+	Logger *slog.Logger
 }
 
 func NewFlowManager(config *FlowManagerConfig) *FlowManager {
 	return &FlowManager{
 		businessAccountId: config.BusinessAccountId,
-		apiAccessToken:    config.ApiAccessToken,
 		requester:         config.Requester,
+		logger:            managerLogger(config.Logger, config.Requester),
 	}
 }
 
+// SetAccessToken makes the manager send its requests with the given access token, see TokenProvider.
+func (m *FlowManager) SetAccessToken(token string) {
+	m.SetTokenProvider(StaticTokenProvider(token))
+}
+
+// SetTokenProvider makes the manager get the access tokens of its requests from the given provider, see TokenProvider.
+func (m *FlowManager) SetTokenProvider(provider TokenProvider) {
+	m.requester = m.requester.CloneWithTokenProvider(provider)
+}
+
+// SetLogger sets the logger used to report the changes made to the flows, the logger of the requester by default.
+func (m *FlowManager) SetLogger(logger *slog.Logger) {
+	m.logger = request_client.LoggerOrDiscard(logger)
+}
+
 type CreateFlowRequest struct {
 	Name        string         `json:"name" validate:"required"`
 	Categories  []FlowCategory `json:"categories" validate:"required,min=1"`
@@ -115,6 +136,11 @@ type CreateFlowResponse struct {
 }
 
 func (m *FlowManager) Create(req CreateFlowRequest) (*CreateFlowResponse, error) {
+	return m.CreateContext(context.Background(), req)
+}
+
+// CreateContext is like Create but uses ctx for the requests made to the Graph API.
+func (m *FlowManager) CreateContext(ctx context.Context, req CreateFlowRequest) (*CreateFlowResponse, error) {
 	apiRequest := m.requester.NewApiRequest(
 		strings.Join([]string{m.businessAccountId, "flows"}, "/"),
 		http.MethodPost,
@@ -126,7 +152,7 @@ func (m *FlowManager) Create(req CreateFlowRequest) (*CreateFlowResponse, error)
 	}
 
 	apiRequest.SetBody(string(jsonBody))
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -136,34 +162,45 @@ func (m *FlowManager) Create(req CreateFlowRequest) (*CreateFlowResponse, error)
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
+	m.logger.DebugContext(ctx, "flow created", "flow_id", result.ID, "validation_errors", len(result.ValidationErrors))
 	return &result, nil
 }
 
+// FetchAll fetches all the flows of the business account, following the pagination until the last page.
 func (m *FlowManager) FetchAll() (*FlowsListResponse, error) {
-	apiRequest := m.requester.NewApiRequest(
-		strings.Join([]string{m.businessAccountId, "flows"}, "/"),
-		http.MethodGet,
-	)
+	return m.FetchAllContext(context.Background())
+}
 
-	response, err := apiRequest.Execute()
+// FetchAllContext is like FetchAll but uses ctx for the requests made to the Graph API.
+func (m *FlowManager) FetchAllContext(ctx context.Context) (*FlowsListResponse, error) {
+	flows, err := m.PaginateFlows(PaginationOptions{}).Collect(ctx)
 	if err != nil {
 		return nil, err
 	}
+	return &FlowsListResponse{Data: flows}, nil
+}
 
-	var result FlowsListResponse
-	if err := json.Unmarshal([]byte(response), &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	return &result, nil
+// PaginateFlows returns a paginator over the flows of the business account.
+func (m *FlowManager) PaginateFlows(options PaginationOptions) *Paginator[FlowNode] {
+	return newPaginator[FlowNode](func() *request_client.ApiRequest {
+		return m.requester.NewApiRequest(
+			strings.Join([]string{m.businessAccountId, "flows"}, "/"),
+			http.MethodGet,
+		)
+	}, options)
 }
 
 func (m *FlowManager) Fetch(flowID string) (*FlowNode, error) {
+	return m.FetchContext(context.Background(), flowID)
+}
+
+// FetchContext is like Fetch but uses ctx for the requests made to the Graph API.
+func (m *FlowManager) FetchContext(ctx context.Context, flowID string) (*FlowNode, error) {
 	fields := "id,name,status,categories,validation_errors,json_version,data_api_version,endpoint_uri,preview,health_status"
 	apiRequest := m.requester.NewApiRequest(flowID, http.MethodGet)
 	apiRequest.AddQueryParam("fields", fields)
 
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -183,7 +220,13 @@ type UpdateFlowRequest struct {
 }
 
 func (m *FlowManager) Update(flowID string, req UpdateFlowRequest) error {
+	return m.UpdateContext(context.Background(), flowID, req)
+}
+
+// UpdateContext is like Update but uses ctx for the requests made to the Graph API.
+func (m *FlowManager) UpdateContext(ctx context.Context, flowID string, req UpdateFlowRequest) error {
 	apiRequest := m.requester.NewApiRequest(flowID, http.MethodPost)
+	apiRequest.SetIdempotent(true)
 
 	jsonBody, err := json.Marshal(req)
 	if err != nil {
@@ -191,8 +234,11 @@ func (m *FlowManager) Update(flowID string, req UpdateFlowRequest) error {
 	}
 
 	apiRequest.SetBody(string(jsonBody))
-	_, err = apiRequest.Execute()
-	return err
+	if _, err := apiRequest.Execute(ctx); err != nil {
+		return err
+	}
+	m.logger.DebugContext(ctx, "flow updated", "flow_id", flowID)
+	return nil
 }
 
 // UploadFlowJSONResponse represents the response from uploading flow JSON
@@ -204,6 +250,11 @@ type UploadFlowJSONResponse struct {
 // UploadFlowJSON uploads or updates the Flow JSON for an existing flow.
 // Meta API requires multipart/form-data for the /{FLOW_ID}/assets endpoint.
 func (m *FlowManager) UploadFlowJSON(flowID string, flowJSON string) (*UploadFlowJSONResponse, error) {
+	return m.UploadFlowJSONContext(context.Background(), flowID, flowJSON)
+}
+
+// UploadFlowJSONContext is like UploadFlowJSON but uses ctx for the requests made to the Graph API.
+func (m *FlowManager) UploadFlowJSONContext(ctx context.Context, flowID string, flowJSON string) (*UploadFlowJSONResponse, error) {
 	// Build multipart form data
 	var buf bytes.Buffer
 	writer := multipart.NewWriter(&buf)
@@ -234,7 +285,7 @@ func (m *FlowManager) UploadFlowJSON(flowID string, flowJSON string) (*UploadFlo
 
 	// Make the request using RequestMultipart
 	path := strings.Join([]string{flowID, "assets"}, "/")
-	response, err := m.requester.RequestMultipart(
+	response, err := m.requester.RequestMultipart(ctx,
 		http.MethodPost,
 		path,
 		&buf,
@@ -249,43 +300,77 @@ func (m *FlowManager) UploadFlowJSON(flowID string, flowJSON string) (*UploadFlo
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
+	if len(result.ValidationErrors) > 0 {
+		m.logger.WarnContext(ctx, "flow JSON has validation errors", "flow_id", flowID, "validation_errors", len(result.ValidationErrors))
+	} else {
+		m.logger.DebugContext(ctx, "flow JSON uploaded", "flow_id", flowID)
+	}
 	return &result, nil
 }
 
 func (m *FlowManager) Publish(flowID string) error {
+	return m.PublishContext(context.Background(), flowID)
+}
+
+// PublishContext is like Publish but uses ctx for the requests made to the Graph API.
+func (m *FlowManager) PublishContext(ctx context.Context, flowID string) error {
 	apiRequest := m.requester.NewApiRequest(
 		strings.Join([]string{flowID, "publish"}, "/"),
 		http.MethodPost,
 	)
 
-	_, err := apiRequest.Execute()
-	return err
+	if _, err := apiRequest.Execute(ctx); err != nil {
+		return err
+	}
+	m.logger.DebugContext(ctx, "flow published", "flow_id", flowID)
+	return nil
 }
 
 func (m *FlowManager) Deprecate(flowID string) error {
+	return m.DeprecateContext(context.Background(), flowID)
+}
+
+// DeprecateContext is like Deprecate but uses ctx for the requests made to the Graph API.
+func (m *FlowManager) DeprecateContext(ctx context.Context, flowID string) error {
 	apiRequest := m.requester.NewApiRequest(
 		strings.Join([]string{flowID, "deprecate"}, "/"),
 		http.MethodPost,
 	)
 
-	_, err := apiRequest.Execute()
-	return err
+	if _, err := apiRequest.Execute(ctx); err != nil {
+		return err
+	}
+	m.logger.DebugContext(ctx, "flow deprecated", "flow_id", flowID)
+	return nil
 }
 
 func (m *FlowManager) Delete(flowID string) error {
+	return m.DeleteContext(context.Background(), flowID)
+}
+
+// DeleteContext is like Delete but uses ctx for the requests made to the Graph API.
+func (m *FlowManager) DeleteContext(ctx context.Context, flowID string) error {
 	apiRequest := m.requester.NewApiRequest(flowID, http.MethodDelete)
 
-	_, err := apiRequest.Execute()
-	return err
+	if _, err := apiRequest.Execute(ctx); err != nil {
+		return err
+	}
+	m.logger.DebugContext(ctx, "flow deleted", "flow_id", flowID)
+	return nil
 }
 
 func (m *FlowManager) GetFlowJSON(flowID string) (string, error) {
+	return m.GetFlowJSONContext(context.Background(), flowID)
+}
+
+// GetFlowJSONContext is like GetFlowJSON but uses ctx for the requests made to the Graph API.
+func (m *FlowManager) GetFlowJSONContext(ctx context.Context, flowID string) (string, error) {
 	apiRequest := m.requester.NewApiRequest(
 		strings.Join([]string{flowID, "assets"}, "/"),
 		http.MethodGet,
 	)
 
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return "", err
 	}
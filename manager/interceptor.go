@@ -0,0 +1,22 @@
+package manager
+
+import (
+	"github.com/gTahidi/wapi.go/internal/request_client"
+)
+
+// RequestHandler sends a request to the Graph API and returns its response.
+type RequestHandler = request_client.RequestHandler
+
+// Interceptor wraps every request sent to the Graph API, for example to add headers, record metrics or
+// capture the fbtrace_id of the responses:
+//
+//	func timing(request *http.Request, next manager.RequestHandler) (*http.Response, error) {
+//		start := time.Now()
+//		response, err := next(request)
+//		log.Printf("%s %s took %s", request.Method, request.URL.Path, time.Since(start))
+//		return response, err
+//	}
+//
+// An interceptor can short-circuit the call by returning a response without calling next.
+// It runs once per attempt, so a retried request goes through the chain again.
+type Interceptor = request_client.Interceptor
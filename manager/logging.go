@@ -0,0 +1,24 @@
+package manager
+
+import (
+	"log/slog"
+
+	"github.com/gTahidi/wapi.go/internal/request_client"
+)
+
+// Attribute keys of the log records of the SDK, for filtering or routing them in a slog.Handler.
+const (
+	LogKeyPhoneNumberId = request_client.LogKeyPhoneNumberId
+	LogKeyMessageId     = request_client.LogKeyMessageId
+	LogKeyEventType     = request_client.LogKeyEventType
+	LogKeyFbtraceId     = request_client.LogKeyFbtraceId
+)
+
+// managerLogger returns the logger of a manager: the configured one, or the logger of its request client,
+// so that the managers created by a client log with the logger of the client.
+func managerLogger(logger *slog.Logger, requester *request_client.RequestClient) *slog.Logger {
+	if logger == nil && requester != nil {
+		return requester.Logger()
+	}
+	return request_client.LoggerOrDiscard(logger)
+}
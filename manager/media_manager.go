@@ -2,9 +2,11 @@ package manager
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
@@ -18,15 +20,32 @@ import (
 // MediaManager is responsible for managing media related operations.
 type MediaManager struct {
 	requester request_client.RequestClient
+	logger    *slog.Logger
 }
 
 // NewMediaManager creates a new instance of MediaManager.
 func NewMediaManager(requester request_client.RequestClient) *MediaManager {
 	return &MediaManager{
 		requester: requester,
+		logger:    requester.Logger(),
 	}
 }
 
+// SetAccessToken makes the manager send its requests with the given access token, see TokenProvider.
+func (mm *MediaManager) SetAccessToken(token string) {
+	mm.SetTokenProvider(StaticTokenProvider(token))
+}
+
+// SetTokenProvider makes the manager get the access tokens of its requests from the given provider, see TokenProvider.
+func (mm *MediaManager) SetTokenProvider(provider TokenProvider) {
+	mm.requester = *mm.requester.CloneWithTokenProvider(provider)
+}
+
+// SetLogger sets the logger used to report uploaded and deleted media, the logger of the requester by default.
+func (mm *MediaManager) SetLogger(logger *slog.Logger) {
+	mm.logger = request_client.LoggerOrDiscard(logger)
+}
+
 type MediaMetadata struct {
 	MessagingProduct string `json:"messaging_product"`
 	Url              string `json:"url"`
@@ -37,11 +56,16 @@ type MediaMetadata struct {
 }
 
 func (mm *MediaManager) GetMediaUrlById(id string) (string, error) {
+	return mm.GetMediaUrlByIdContext(context.Background(), id)
+}
+
+// GetMediaUrlByIdContext is like GetMediaUrlById but uses ctx for the requests made to the Graph API.
+func (mm *MediaManager) GetMediaUrlByIdContext(ctx context.Context, id string) (string, error) {
 	// Build GET request to: e.g. "<MEDIA_ID>" (the request client automatically prefixes the base URL and version)
 	apiRequest := mm.requester.NewApiRequest(id, http.MethodGet)
 
 	// Execute the request and get the raw JSON response
-	rawResponse, err := apiRequest.Execute()
+	rawResponse, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -59,15 +83,47 @@ func (mm *MediaManager) GetMediaUrlById(id string) (string, error) {
 	return res.Url, nil
 }
 
+// BatchGetMediaMetadata fetches the metadata of the media with the given IDs in Graph API batch requests of up to 50 media.
+// The returned metadata are in the order of the IDs, with nil for the ones which could not be fetched,
+// and the map holds the error of each of those by index.
+func (mm *MediaManager) BatchGetMediaMetadata(ids []string) ([]*MediaMetadata, map[int]error) {
+	return mm.BatchGetMediaMetadataContext(context.Background(), ids)
+}
+
+// BatchGetMediaMetadataContext is like BatchGetMediaMetadata but uses ctx for the requests made to the Graph API.
+func (mm *MediaManager) BatchGetMediaMetadataContext(ctx context.Context, ids []string) ([]*MediaMetadata, map[int]error) {
+	requests := make([]*request_client.ApiRequest, 0, len(ids))
+	for _, id := range ids {
+		requests = append(requests, mm.requester.NewApiRequest(id, http.MethodGet))
+	}
+
+	metadata := make([]*MediaMetadata, len(ids))
+	errs := make(map[int]error)
+	for i, response := range executeBatched(ctx, &mm.requester, requests) {
+		var media MediaMetadata
+		if err := response.Decode(&media); err != nil {
+			errs[i] = err
+			continue
+		}
+		metadata[i] = &media
+	}
+	return metadata, errs
+}
+
 type DeleteSuccessResponse struct {
 	Success bool `json:"success"`
 }
 
 func (mm *MediaManager) DeleteMedia(id string) (string, error) {
+	return mm.DeleteMediaContext(context.Background(), id)
+}
+
+// DeleteMediaContext is like DeleteMedia but uses ctx for the requests made to the Graph API.
+func (mm *MediaManager) DeleteMediaContext(ctx context.Context, id string) (string, error) {
 	// The path becomes "media/<MEDIA_ID>"
 	apiRequest := mm.requester.NewApiRequest(strings.Join([]string{"media", id}, "/"), http.MethodDelete)
 
-	rawResponse, err := apiRequest.Execute()
+	rawResponse, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -82,11 +138,17 @@ func (mm *MediaManager) DeleteMedia(id string) (string, error) {
 		return "", fmt.Errorf("media deletion failed or returned success=false: %s", rawResponse)
 	}
 
+	mm.logger.DebugContext(ctx, "media deleted", "media_id", id)
 	return "media deleted successfully", nil
 }
 
 // UploadMedia uploads a media file to WhatsApp's Cloud API.
 func (mm *MediaManager) UploadMedia(phoneNumberId string, file io.Reader, filename, mimeType string) (string, error) {
+	return mm.UploadMediaContext(context.Background(), phoneNumberId, file, filename, mimeType)
+}
+
+// UploadMediaContext is like UploadMedia but uses ctx for the requests made to the Graph API.
+func (mm *MediaManager) UploadMediaContext(ctx context.Context, phoneNumberId string, file io.Reader, filename, mimeType string) (string, error) {
 	// 1. Build the multipart form in memory
 	body := new(bytes.Buffer)
 	writer := multipart.NewWriter(body)
@@ -116,7 +178,7 @@ func (mm *MediaManager) UploadMedia(phoneNumberId string, file io.Reader, filena
 
 	contentType := writer.FormDataContentType()
 
-	responseBody, err := mm.requester.RequestMultipart(http.MethodPost, apiPath, body, contentType)
+	responseBody, err := mm.requester.RequestMultipart(ctx, http.MethodPost, apiPath, body, contentType)
 	if err != nil {
 		return "", fmt.Errorf("error uploading media: %w", err)
 	}
@@ -132,6 +194,7 @@ func (mm *MediaManager) UploadMedia(phoneNumberId string, file io.Reader, filena
 		return "", fmt.Errorf("no media id in response: %s", responseBody)
 	}
 
+	mm.logger.DebugContext(ctx, "media uploaded", request_client.LogKeyPhoneNumberId, phoneNumberId, "media_id", result.ID, "mime_type", mimeType)
 	return result.ID, nil
 }
 
@@ -150,6 +213,11 @@ type ResumableUploadResult struct {
 // appID is your Meta App ID, fileLength is the size in bytes, fileType is the MIME type (e.g., "image/png").
 // Returns the upload session ID.
 func (mm *MediaManager) CreateResumableUploadSession(appID string, fileLength int64, fileType string) (string, error) {
+	return mm.CreateResumableUploadSessionContext(context.Background(), appID, fileLength, fileType)
+}
+
+// CreateResumableUploadSessionContext is like CreateResumableUploadSession but uses ctx for the requests made to the Graph API.
+func (mm *MediaManager) CreateResumableUploadSessionContext(ctx context.Context, appID string, fileLength int64, fileType string) (string, error) {
 	// POST to /{app-id}/uploads with file_length, file_type, file_name
 	path := fmt.Sprintf("%s/uploads", appID)
 
@@ -165,7 +233,7 @@ func (mm *MediaManager) CreateResumableUploadSession(appID string, fileLength in
 	apiRequest := mm.requester.NewApiRequest(path, http.MethodPost)
 	apiRequest.SetBody(string(bodyJSON))
 
-	rawResponse, err := apiRequest.Execute()
+	rawResponse, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return "", fmt.Errorf("failed to create upload session: %w", err)
 	}
@@ -187,47 +255,33 @@ func (mm *MediaManager) CreateResumableUploadSession(appID string, fileLength in
 // fileData is the raw file bytes, fileOffset is the starting byte offset (usually 0).
 // Returns the media handle to use in template header_handle.
 func (mm *MediaManager) UploadResumableMedia(sessionID string, fileData []byte, fileOffset int64) (string, error) {
+	return mm.UploadResumableMediaContext(context.Background(), sessionID, fileData, fileOffset)
+}
+
+// UploadResumableMediaContext is like UploadResumableMedia but uses ctx for the requests made to the Graph API.
+func (mm *MediaManager) UploadResumableMediaContext(ctx context.Context, sessionID string, fileData []byte, fileOffset int64) (string, error) {
 	// POST to /{upload-session-id} with file data in body
 	// Headers: Authorization, file_offset
-
-	requestPath := fmt.Sprintf("%s://%s/%s/%s",
-		request_client.REQUEST_PROTOCOL,
-		request_client.BASE_URL,
-		request_client.API_VERSION,
-		sessionID,
-	)
-
-	httpRequest, err := http.NewRequest(http.MethodPost, requestPath, bytes.NewReader(fileData))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
-
-	httpRequest.Header.Set("Authorization", fmt.Sprintf("OAuth %s", mm.requester.ApiAccessToken()))
-	httpRequest.Header.Set("file_offset", strconv.FormatInt(fileOffset, 10))
-
-	httpClient := &http.Client{}
-	response, err := httpClient.Do(httpRequest)
+	responseBody, err := mm.requester.RequestRaw(ctx, request_client.RawRequestParams{
+		Method:     http.MethodPost,
+		Path:       sessionID,
+		Body:       bytes.NewReader(fileData),
+		AuthScheme: "OAuth",
+		Headers: map[string]string{
+			"file_offset": strconv.FormatInt(fileOffset, 10),
+		},
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to execute upload request: %w", err)
-	}
-	defer response.Body.Close()
-
-	respBody, err := io.ReadAll(response.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	if response.StatusCode < 200 || response.StatusCode >= 300 {
-		return "", fmt.Errorf("upload failed with status %d: %s", response.StatusCode, string(respBody))
+		return "", fmt.Errorf("upload failed: %w", err)
 	}
 
 	var result ResumableUploadResult
-	if err := json.Unmarshal(respBody, &result); err != nil {
+	if err := json.Unmarshal([]byte(responseBody), &result); err != nil {
 		return "", fmt.Errorf("failed to parse upload response: %w", err)
 	}
 
 	if result.Handle == "" {
-		return "", fmt.Errorf("no media handle in response: %s", string(respBody))
+		return "", fmt.Errorf("no media handle in response: %s", responseBody)
 	}
 
 	return result.Handle, nil
@@ -237,14 +291,19 @@ func (mm *MediaManager) UploadResumableMedia(sessionID string, fileData []byte,
 // This is a convenience method that combines CreateResumableUploadSession and UploadResumableMedia.
 // appID is your Meta App ID, fileData is the raw file bytes, fileType is the MIME type.
 func (mm *MediaManager) UploadMediaForTemplate(appID string, fileData []byte, fileType string) (string, error) {
+	return mm.UploadMediaForTemplateContext(context.Background(), appID, fileData, fileType)
+}
+
+// UploadMediaForTemplateContext is like UploadMediaForTemplate but uses ctx for the requests made to the Graph API.
+func (mm *MediaManager) UploadMediaForTemplateContext(ctx context.Context, appID string, fileData []byte, fileType string) (string, error) {
 	// Step 1: Create upload session
-	sessionID, err := mm.CreateResumableUploadSession(appID, int64(len(fileData)), fileType)
+	sessionID, err := mm.CreateResumableUploadSessionContext(ctx, appID, int64(len(fileData)), fileType)
 	if err != nil {
 		return "", fmt.Errorf("failed to create upload session: %w", err)
 	}
 
 	// Step 2: Upload the file data
-	handle, err := mm.UploadResumableMedia(sessionID, fileData, 0)
+	handle, err := mm.UploadResumableMediaContext(ctx, sessionID, fileData, 0)
 	if err != nil {
 		return "", fmt.Errorf("failed to upload media: %w", err)
 	}
@@ -1,8 +1,10 @@
 package manager
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
 
@@ -12,8 +14,10 @@ import (
 
 // MessageManager is responsible for managing messages.
 type MessageManager struct {
-	requester     request_client.RequestClient
-	PhoneNumberId string
+	requester         request_client.RequestClient
+	PhoneNumberId     string
+	throughputLimiter *ThroughputLimiter
+	logger            *slog.Logger
 }
 
 // NewMessageManager creates a new instance of MessageManager.
@@ -21,9 +25,39 @@ func NewMessageManager(requester request_client.RequestClient, phoneNumberId str
 	return &MessageManager{
 		requester:     requester,
 		PhoneNumberId: phoneNumberId,
+		logger:        requester.Logger(),
 	}
 }
 
+// SetAccessToken makes the manager send its requests with the given access token, see TokenProvider.
+func (mm *MessageManager) SetAccessToken(token string) {
+	mm.SetTokenProvider(StaticTokenProvider(token))
+}
+
+// SetTokenProvider makes the manager get the access tokens of its requests from the given provider, see TokenProvider.
+func (mm *MessageManager) SetTokenProvider(provider TokenProvider) {
+	mm.requester = *mm.requester.CloneWithTokenProvider(provider)
+}
+
+// SetLogger sets the logger used to report sent messages, the logger of the requester by default.
+func (mm *MessageManager) SetLogger(logger *slog.Logger) {
+	mm.logger = request_client.LoggerOrDiscard(logger)
+}
+
+// SetThroughputLimiter makes the manager pace the messages it sends with the given limiter,
+// which can be shared with the managers of other phone numbers. A nil limiter disables pacing.
+func (mm *MessageManager) SetThroughputLimiter(limiter *ThroughputLimiter) {
+	mm.throughputLimiter = limiter
+}
+
+// waitForThroughput waits for the throughput limiter, if any, to let a message to the given recipient go out.
+func (mm *MessageManager) waitForThroughput(ctx context.Context, recipient string) error {
+	if mm.throughputLimiter == nil {
+		return nil
+	}
+	return mm.throughputLimiter.Wait(ctx, mm.PhoneNumberId, recipient)
+}
+
 // MessageSendResponse represents the structured API response for sending a message.
 type MessageSendResponse struct {
 	MessagingProduct string `json:"messaging_product"`
@@ -59,6 +93,11 @@ type StatusResponse struct {
 // Reply sends a reply message using the provided BaseMessage and returns a structured response.
 // If the API response contains an error, it returns that error.
 func (mm *MessageManager) Reply(message components.BaseMessage, phoneNumber string, replyTo string) (*MessageSendResponse, error) {
+	return mm.ReplyContext(context.Background(), message, phoneNumber, replyTo)
+}
+
+// ReplyContext is like Reply but uses ctx for the requests made to the Graph API.
+func (mm *MessageManager) ReplyContext(ctx context.Context, message components.BaseMessage, phoneNumber string, replyTo string) (*MessageSendResponse, error) {
 	body, err := message.ToJson(components.ApiCompatibleJsonConverterConfigs{
 		SendToPhoneNumber: phoneNumber,
 		ReplyToMessageId:  replyTo,
@@ -67,32 +106,17 @@ func (mm *MessageManager) Reply(message components.BaseMessage, phoneNumber stri
 		return nil, fmt.Errorf("error converting message to json: %v", err)
 	}
 
-	// Build the API request.
-	apiRequest := mm.requester.NewApiRequest(strings.Join([]string{mm.PhoneNumberId, "messages"}, "/"), http.MethodPost)
-	apiRequest.SetBody(string(body))
-	responseStr, err := apiRequest.Execute()
-	if err != nil {
-		return nil, err
-	}
-
-	// Unmarshal the API response.
-	var sendResponse MessageSendResponse
-	err = json.Unmarshal([]byte(responseStr), &sendResponse)
-	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling response: %v", err)
-	}
-
-	// If an error object is present in the response, return it.
-	if sendResponse.Error != nil {
-		return &sendResponse, fmt.Errorf("error sending message: %s", sendResponse.Error.Message)
-	}
-
-	return &sendResponse, nil
+	return mm.dispatch(ctx, phoneNumber, body)
 }
 
 // Send sends a message using the provided BaseMessage and returns a structured response.
 // If the API response contains an error, it returns that error.
 func (mm *MessageManager) Send(message components.BaseMessage, phoneNumber string) (*MessageSendResponse, error) {
+	return mm.SendContext(context.Background(), message, phoneNumber)
+}
+
+// SendContext is like Send but uses ctx for the requests made to the Graph API.
+func (mm *MessageManager) SendContext(ctx context.Context, message components.BaseMessage, phoneNumber string) (*MessageSendResponse, error) {
 	// Convert the message to JSON.
 	body, err := message.ToJson(components.ApiCompatibleJsonConverterConfigs{
 		SendToPhoneNumber: phoneNumber,
@@ -101,27 +125,7 @@ func (mm *MessageManager) Send(message components.BaseMessage, phoneNumber strin
 		return nil, fmt.Errorf("error converting message to json: %v", err)
 	}
 
-	// Build the API request.
-	apiRequest := mm.requester.NewApiRequest(strings.Join([]string{mm.PhoneNumberId, "messages"}, "/"), http.MethodPost)
-	apiRequest.SetBody(string(body))
-	responseStr, err := apiRequest.Execute()
-	if err != nil {
-		return nil, err
-	}
-
-	// Unmarshal the API response.
-	var sendResponse MessageSendResponse
-	err = json.Unmarshal([]byte(responseStr), &sendResponse)
-	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling response: %v", err)
-	}
-
-	// If an error object is present in the response, return it.
-	if sendResponse.Error != nil {
-		return &sendResponse, fmt.Errorf("error sending message: %s", sendResponse.Error.Message)
-	}
-
-	return &sendResponse, nil
+	return mm.dispatch(ctx, phoneNumber, body)
 }
 
 // authenticationAwareMessage is implemented by messages that can report whether
@@ -137,6 +141,11 @@ type authenticationAwareMessage interface {
 // Authentication-category templates (OTP / verification codes) cannot be
 // delivered to a BSUID and are rejected before the request is made.
 func (mm *MessageManager) SendToUser(message components.BaseMessage, userId string) (*MessageSendResponse, error) {
+	return mm.SendToUserContext(context.Background(), message, userId)
+}
+
+// SendToUserContext is like SendToUser but uses ctx for the requests made to the Graph API.
+func (mm *MessageManager) SendToUserContext(ctx context.Context, message components.BaseMessage, userId string) (*MessageSendResponse, error) {
 	if a, ok := message.(authenticationAwareMessage); ok && a.IsAuthentication() {
 		return nil, fmt.Errorf("authentication templates cannot be sent to a business-scoped user ID (BSUID)")
 	}
@@ -148,12 +157,17 @@ func (mm *MessageManager) SendToUser(message components.BaseMessage, userId stri
 		return nil, fmt.Errorf("error converting message to json: %v", err)
 	}
 
-	return mm.dispatch(body)
+	return mm.dispatch(ctx, userId, body)
 }
 
 // ReplyToUser sends a reply to a business-scoped user ID (BSUID), quoting the
 // message identified by replyTo. See SendToUser for the BSUID restrictions.
 func (mm *MessageManager) ReplyToUser(message components.BaseMessage, userId string, replyTo string) (*MessageSendResponse, error) {
+	return mm.ReplyToUserContext(context.Background(), message, userId, replyTo)
+}
+
+// ReplyToUserContext is like ReplyToUser but uses ctx for the requests made to the Graph API.
+func (mm *MessageManager) ReplyToUserContext(ctx context.Context, message components.BaseMessage, userId string, replyTo string) (*MessageSendResponse, error) {
 	if a, ok := message.(authenticationAwareMessage); ok && a.IsAuthentication() {
 		return nil, fmt.Errorf("authentication templates cannot be sent to a business-scoped user ID (BSUID)")
 	}
@@ -166,15 +180,19 @@ func (mm *MessageManager) ReplyToUser(message components.BaseMessage, userId str
 		return nil, fmt.Errorf("error converting message to json: %v", err)
 	}
 
-	return mm.dispatch(body)
+	return mm.dispatch(ctx, userId, body)
 }
 
 // dispatch posts an already-serialized message body to the messages endpoint and
 // parses the structured response.
-func (mm *MessageManager) dispatch(body []byte) (*MessageSendResponse, error) {
+func (mm *MessageManager) dispatch(ctx context.Context, recipient string, body []byte) (*MessageSendResponse, error) {
+	if err := mm.waitForThroughput(ctx, recipient); err != nil {
+		return nil, err
+	}
+
 	apiRequest := mm.requester.NewApiRequest(strings.Join([]string{mm.PhoneNumberId, "messages"}, "/"), http.MethodPost)
 	apiRequest.SetBody(string(body))
-	responseStr, err := apiRequest.Execute()
+	responseStr, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -186,16 +204,19 @@ func (mm *MessageManager) dispatch(body []byte) (*MessageSendResponse, error) {
 	}
 
 	if sendResponse.Error != nil {
-		return &sendResponse, fmt.Errorf("error sending message: %s", sendResponse.Error.Message)
+		return &sendResponse, fmt.Errorf("error sending message: %w", request_client.ParseGraphAPIError(http.StatusOK, []byte(responseStr)))
 	}
 
+	for _, message := range sendResponse.Messages {
+		mm.logger.DebugContext(ctx, "message sent", request_client.LogKeyPhoneNumberId, mm.PhoneNumberId, request_client.LogKeyMessageId, message.ID)
+	}
 	return &sendResponse, nil
 }
 
 // ReadMessage marks a message as read.
 // messageId: The ID of the message to mark as read
 // showTyping: Whether to show typing indicator (will auto-dismiss after 25 seconds or when you respond)
-func (mm *MessageManager) readMessage(messageId string, showTyping bool) error {
+func (mm *MessageManager) readMessage(ctx context.Context, messageId string, showTyping bool) error {
 	// Create the request body for marking message as read
 	requestBody := map[string]interface{}{
 		"messaging_product": "whatsapp",
@@ -218,9 +239,11 @@ func (mm *MessageManager) readMessage(messageId string, showTyping bool) error {
 	// Build the API request
 	apiRequest := mm.requester.NewApiRequest(strings.Join([]string{mm.PhoneNumberId, "messages"}, "/"), http.MethodPost)
 	apiRequest.SetBody(string(body))
-	responseStr, err := apiRequest.Execute()
+	// marking a message as read more than once is harmless
+	apiRequest.SetIdempotent(true)
+	responseStr, err := apiRequest.Execute(ctx)
 	if err != nil {
-		return fmt.Errorf("error executing read message request: %v", err)
+		return fmt.Errorf("error executing read message request: %w", err)
 	}
 
 	// Parse the response to check for errors
@@ -231,7 +254,7 @@ func (mm *MessageManager) readMessage(messageId string, showTyping bool) error {
 	}
 
 	if statusResponse.Error != nil {
-		return fmt.Errorf("error marking message as read: %s", statusResponse.Error.Message)
+		return fmt.Errorf("error marking message as read: %w", request_client.ParseGraphAPIError(http.StatusOK, []byte(responseStr)))
 	}
 
 	return nil
@@ -240,11 +263,21 @@ func (mm *MessageManager) readMessage(messageId string, showTyping bool) error {
 // ReadMessageWithTyping marks a message as read and shows typing indicator.
 // This is a convenience method for ReadMessage(messageId, true).
 func (mm *MessageManager) ReadMessageWithTyping(messageId string) error {
-	return mm.readMessage(messageId, true)
+	return mm.ReadMessageWithTypingContext(context.Background(), messageId)
+}
+
+// ReadMessageWithTypingContext is like ReadMessageWithTyping but uses ctx for the requests made to the Graph API.
+func (mm *MessageManager) ReadMessageWithTypingContext(ctx context.Context, messageId string) error {
+	return mm.readMessage(ctx, messageId, true)
 }
 
 // ReadMessageOnly marks a message as read without showing typing indicator.
 // This is a convenience method for ReadMessage(messageId, false).
 func (mm *MessageManager) ReadMessageOnly(messageId string) error {
-	return mm.readMessage(messageId, false)
+	return mm.ReadMessageOnlyContext(context.Background(), messageId)
+}
+
+// ReadMessageOnlyContext is like ReadMessageOnly but uses ctx for the requests made to the Graph API.
+func (mm *MessageManager) ReadMessageOnlyContext(ctx context.Context, messageId string) error {
+	return mm.readMessage(ctx, messageId, false)
 }
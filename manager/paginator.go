@@ -0,0 +1,162 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"strconv"
+
+	"github.com/gTahidi/wapi.go/internal"
+	"github.com/gTahidi/wapi.go/internal/request_client"
+)
+
+// PaginationOptions controls how a Paginator walks through a list, all the fields are optional.
+type PaginationOptions struct {
+	// PageSize is the number of items requested per page, the Graph API default is used when zero.
+	PageSize int
+	// After starts the pagination right after the given cursor.
+	After string
+	// Before walks the list backwards, starting right before the given cursor.
+	Before string
+}
+
+// paginatedResponse is the shape of every list response of the Graph API.
+type paginatedResponse[T any] struct {
+	Data []T `json:"data"`
+	internal.WhatsAppBusinessApiPaginationMeta
+}
+
+// Paginator walks through a list of the Graph API page by page, following the cursors of the responses.
+// A Paginator is not safe for concurrent use.
+//
+//	paginator := templateManager.PaginateTemplates(manager.PaginationOptions{PageSize: 100})
+//	for template, err := range paginator.All(ctx) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(template.Name)
+//	}
+type Paginator[T any] struct {
+	newRequest func() *request_client.ApiRequest
+	pageSize   int
+	backward   bool
+	cursor     string
+	done       bool
+	pending    []T // pending holds the items of the last page not yielded yet by All
+	before     string
+	after      string
+}
+
+// newPaginator creates a paginator sending the requests built by newRequest, to which the paging query params are added.
+func newPaginator[T any](newRequest func() *request_client.ApiRequest, options PaginationOptions) *Paginator[T] {
+	paginator := &Paginator[T]{
+		newRequest: newRequest,
+		pageSize:   options.PageSize,
+		cursor:     options.After,
+	}
+	if options.After == "" && options.Before != "" {
+		paginator.backward = true
+		paginator.cursor = options.Before
+	}
+	return paginator
+}
+
+// HasNext reports whether there may be more items, left over by All or in pages to fetch.
+func (paginator *Paginator[T]) HasNext() bool {
+	return len(paginator.pending) > 0 || !paginator.done
+}
+
+// Cursors returns the before and after cursors of the last fetched page,
+// which can be stored to resume the pagination later with PaginationOptions. Resuming from the after cursor
+// skips the items of the last page which were left over by All.
+func (paginator *Paginator[T]) Cursors() (before string, after string) {
+	return paginator.before, paginator.after
+}
+
+// Next fetches the next page, it returns no items and no error once the last page was fetched.
+// A failed page can be fetched again by calling Next again. The items of the last page left over by
+// All are returned first, without fetching a page.
+func (paginator *Paginator[T]) Next(ctx context.Context) ([]T, error) {
+	if len(paginator.pending) > 0 {
+		page := paginator.pending
+		paginator.pending = nil
+		return page, nil
+	}
+	if paginator.done {
+		return nil, nil
+	}
+
+	apiRequest := paginator.newRequest()
+	if paginator.pageSize > 0 {
+		apiRequest.AddQueryParam("limit", strconv.Itoa(paginator.pageSize))
+	}
+	if paginator.cursor != "" {
+		if paginator.backward {
+			apiRequest.AddQueryParam("before", paginator.cursor)
+		} else {
+			apiRequest.AddQueryParam("after", paginator.cursor)
+		}
+	}
+
+	response, err := apiRequest.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var page paginatedResponse[T]
+	if err := json.Unmarshal([]byte(response), &page); err != nil {
+		return nil, fmt.Errorf("error unmarshalling page: %w", err)
+	}
+
+	paginator.before = page.Paging.Cursors.Before
+	paginator.after = page.Paging.Cursors.After
+	if paginator.backward {
+		paginator.cursor = paginator.before
+		paginator.done = page.Paging.Previous == ""
+	} else {
+		paginator.cursor = paginator.after
+		paginator.done = page.Paging.Next == ""
+	}
+	if paginator.cursor == "" || len(page.Data) == 0 {
+		paginator.done = true
+	}
+	return page.Data, nil
+}
+
+// All returns an iterator over the items of all the remaining pages. The iteration stops after yielding the
+// first error, or when the loop breaks, it can be resumed by ranging over All again: the items of the current
+// page which were not yielded yet come first.
+func (paginator *Paginator[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for paginator.HasNext() {
+			if len(paginator.pending) == 0 {
+				page, err := paginator.Next(ctx)
+				if err != nil {
+					var zero T
+					yield(zero, err)
+					return
+				}
+				paginator.pending = page
+			}
+			for len(paginator.pending) > 0 {
+				item := paginator.pending[0]
+				paginator.pending = paginator.pending[1:]
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Collect fetches all the remaining pages and returns their items.
+func (paginator *Paginator[T]) Collect(ctx context.Context) ([]T, error) {
+	items := []T{}
+	for item, err := range paginator.All(ctx) {
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
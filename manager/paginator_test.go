@@ -0,0 +1,125 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gTahidi/wapi.go/internal/request_client"
+)
+
+// paginatorTestPages are the pages served by newPaginatorTestServer, by the cursor they are requested with.
+var paginatorTestPages = map[string]string{
+	"":   `{"data": [1, 2, 3], "paging": {"cursors": {"before": "b0", "after": "c1"}, "next": "next-url"}}`,
+	"c1": `{"data": [4, 5], "paging": {"cursors": {"before": "b1", "after": "c2"}, "next": "next-url"}}`,
+	"c2": `{"data": [6], "paging": {"cursors": {"before": "b2", "after": "c3"}}}`,
+}
+
+// newPaginatorTestServer serves paginatorTestPages, failing the requests for the cursors of failures once,
+// and returns a paginator over them with the number of requests it received.
+func newPaginatorTestServer(t *testing.T, options PaginationOptions, failures ...string) (*Paginator[int], *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	failed := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		cursor := r.URL.Query().Get("after")
+		if slices.Contains(failures, cursor) && !failed[cursor] {
+			failed[cursor] = true
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"message": "Invalid parameter", "code": 100}}`))
+			return
+		}
+		page, ok := paginatorTestPages[cursor]
+		if !ok {
+			t.Errorf("unexpected cursor %q", cursor)
+		}
+		w.Write([]byte(page))
+	}))
+	t.Cleanup(server.Close)
+	requester := request_client.NewRequestClient("token",
+		request_client.WithRequestProtocol("http"),
+		request_client.WithBaseUrl(strings.TrimPrefix(server.URL, "http://")),
+		request_client.WithRetryPolicy(NoRetryPolicy()),
+	)
+	return newPaginator[int](func() *request_client.ApiRequest {
+		return requester.NewApiRequest("123/items", http.MethodGet)
+	}, options), &requests
+}
+
+func TestPaginatorAllResumesAfterBreak(t *testing.T) {
+	for _, breakAfter := range []int{1, 2, 3, 4, 5, 6} {
+		t.Run(fmt.Sprintf("break after %d items", breakAfter), func(t *testing.T) {
+			paginator, requests := newPaginatorTestServer(t, PaginationOptions{})
+			items := []int{}
+			for item, err := range paginator.All(context.Background()) {
+				if err != nil {
+					t.Fatal(err)
+				}
+				items = append(items, item)
+				if len(items) == breakAfter {
+					break
+				}
+			}
+			rest, err := paginator.Collect(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			items = append(items, rest...)
+
+			if want := []int{1, 2, 3, 4, 5, 6}; !slices.Equal(items, want) {
+				t.Errorf("got items %v, want %v", items, want)
+			}
+			if got := requests.Load(); got != 3 {
+				t.Errorf("got %d requests, want 3", got)
+			}
+			if paginator.HasNext() {
+				t.Error("HasNext() = true after the last page")
+			}
+		})
+	}
+}
+
+func TestPaginatorAllResumesAfterError(t *testing.T) {
+	paginator, requests := newPaginatorTestServer(t, PaginationOptions{}, "c1")
+	items, err := paginator.Collect(context.Background())
+	if err == nil {
+		t.Fatal("Collect() returned no error for the failed page")
+	}
+	if want := []int{1, 2, 3}; !slices.Equal(items, want) {
+		t.Errorf("got items %v before the error, want %v", items, want)
+	}
+
+	rest, err := paginator.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{4, 5, 6}; !slices.Equal(rest, want) {
+		t.Errorf("got items %v after the error, want %v", rest, want)
+	}
+	if got := requests.Load(); got != 4 {
+		t.Errorf("got %d requests, want 4", got)
+	}
+}
+
+func TestPaginatorNextReturnsItemsLeftOverByAll(t *testing.T) {
+	paginator, _ := newPaginatorTestServer(t, PaginationOptions{After: "c1"})
+	for range paginator.All(context.Background()) {
+		break
+	}
+	page, err := paginator.Next(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{5}; !slices.Equal(page, want) {
+		t.Errorf("Next() = %v, want the left over items %v", page, want)
+	}
+	if before, after := paginator.Cursors(); before != "b1" || after != "c2" {
+		t.Errorf("Cursors() = %q, %q, want b1, c2", before, after)
+	}
+}
@@ -1,7 +1,9 @@
 package manager
 
 import (
+	"context"
 	"encoding/json"
+	"log/slog"
 	"net/http"
 	"strings"
 
@@ -12,26 +14,45 @@ import (
 // PhoneNumberManager is responsible for managing phone numbers for WhatsApp Business API and phone number specific operations.
 type PhoneNumberManager struct {
 	businessAccountId string
-	apiAccessToken    string
 	requester         *request_client.RequestClient
+	logger            *slog.Logger
 }
 
 // PhoneNumberManagerConfig holds the configuration for PhoneNumberManager.
 type PhoneNumberManagerConfig struct {
 	BusinessAccountId string
-	ApiAccessToken    string
-	Requester         *request_client.RequestClient
+	// Deprecated: ApiAccessToken is ignored, the requests are sent with the access token of Requester,
+	// use SetAccessToken to give the manager a token of its own.
+	ApiAccessToken string
+	Requester      *request_client.RequestClient
+	// Logger is used to report the phone numbers and QR codes created and verified, the logger of the requester is used when it is nil.
+	Logger *slog.Logger
 }
 
 // NewPhoneNumberManager creates a new instance of PhoneNumberManager.
 func NewPhoneNumberManager(config *PhoneNumberManagerConfig) *PhoneNumberManager {
 	return &PhoneNumberManager{
-		apiAccessToken:    config.ApiAccessToken,
 		businessAccountId: config.BusinessAccountId,
 		requester:         config.Requester,
+		logger:            managerLogger(config.Logger, config.Requester),
 	}
 }
 
+// SetAccessToken makes the manager send its requests with the given access token, see TokenProvider.
+func (manager *PhoneNumberManager) SetAccessToken(token string) {
+	manager.SetTokenProvider(StaticTokenProvider(token))
+}
+
+// SetTokenProvider makes the manager get the access tokens of its requests from the given provider, see TokenProvider.
+func (manager *PhoneNumberManager) SetTokenProvider(provider TokenProvider) {
+	manager.requester = manager.requester.CloneWithTokenProvider(provider)
+}
+
+// SetLogger sets the logger used to report the phone numbers and QR codes created and verified, the logger of the requester by default.
+func (manager *PhoneNumberManager) SetLogger(logger *slog.Logger) {
+	manager.logger = request_client.LoggerOrDiscard(logger)
+}
+
 type WhatsappBusinessAccountPhoneNumberCodeVerificationStatus string
 
 const (
@@ -151,32 +172,41 @@ type WhatsappBusinessAccountPhoneNumberEdge struct {
 	Summary string                                     `json:"summary,omitempty"`
 }
 
-// FetchAll fetches all phone numbers based on the provided filters.
+// FetchAll fetches all phone numbers based on the provided filters, following the pagination until the last page.
 func (manager *PhoneNumberManager) FetchAll(getSandBoxNumbers bool) (*WhatsappBusinessAccountPhoneNumberEdge, error) {
-	apiRequest := manager.requester.NewApiRequest(strings.Join([]string{manager.businessAccountId, "/", "phone_numbers"}, ""), http.MethodGet)
-
-	apiRequest.AddQueryParam("fields", "id,account_mode,certificate,code_verification_status,conversational_automation,display_phone_number,health_status,eligibility_for_api_business_global_search,is_official_business_account,is_on_biz_app,is_pin_enabled,is_preverified_number,last_onboarded_time,messaging_limit_tier,name_status,new_certificate,new_display_name,new_name_status,official_business_account,platform_type,quality_score,search_visibility,status,throughput,verified_name")
-	apiRequest.AddQueryParam("filtering", `[{"field":"account_mode","operator":"EQUAL","value":"LIVE"}]`)
-	response, err := apiRequest.Execute()
+	return manager.FetchAllContext(context.Background(), getSandBoxNumbers)
+}
 
+// FetchAllContext is like FetchAll but uses ctx for the requests made to the Graph API.
+func (manager *PhoneNumberManager) FetchAllContext(ctx context.Context, getSandBoxNumbers bool) (*WhatsappBusinessAccountPhoneNumberEdge, error) {
+	phoneNumbers, err := manager.PaginatePhoneNumbers(getSandBoxNumbers, PaginationOptions{}).Collect(ctx)
 	if err != nil {
 		return nil, err
 	}
+	return &WhatsappBusinessAccountPhoneNumberEdge{Data: phoneNumbers}, nil
+}
 
-	var responseToReturn WhatsappBusinessAccountPhoneNumberEdge
-	err = json.Unmarshal([]byte(response), &responseToReturn)
-	if err != nil {
-		return nil, err
-	}
+// PaginatePhoneNumbers returns a paginator over the phone numbers of the business account.
+func (manager *PhoneNumberManager) PaginatePhoneNumbers(getSandBoxNumbers bool, options PaginationOptions) *Paginator[WhatsappBusinessAccountPhoneNumber] {
+	return newPaginator[WhatsappBusinessAccountPhoneNumber](func() *request_client.ApiRequest {
+		apiRequest := manager.requester.NewApiRequest(strings.Join([]string{manager.businessAccountId, "/", "phone_numbers"}, ""), http.MethodGet)
 
-	return &responseToReturn, nil
+		apiRequest.AddQueryParam("fields", "id,account_mode,certificate,code_verification_status,conversational_automation,display_phone_number,health_status,eligibility_for_api_business_global_search,is_official_business_account,is_on_biz_app,is_pin_enabled,is_preverified_number,last_onboarded_time,messaging_limit_tier,name_status,new_certificate,new_display_name,new_name_status,official_business_account,platform_type,quality_score,search_visibility,status,throughput,verified_name")
+		apiRequest.AddQueryParam("filtering", `[{"field":"account_mode","operator":"EQUAL","value":"LIVE"}]`)
+		return apiRequest
+	}, options)
 }
 
 // Fetch fetches a phone number by its ID.
 func (manager *PhoneNumberManager) Fetch(phoneNumberId string) (*WhatsappBusinessAccountPhoneNumber, error) {
+	return manager.FetchContext(context.Background(), phoneNumberId)
+}
+
+// FetchContext is like Fetch but uses ctx for the requests made to the Graph API.
+func (manager *PhoneNumberManager) FetchContext(ctx context.Context, phoneNumberId string) (*WhatsappBusinessAccountPhoneNumber, error) {
 	apiRequest := manager.requester.NewApiRequest(phoneNumberId, http.MethodGet)
 	apiRequest.AddQueryParam("fields", "id,account_mode,certificate,code_verification_status,conversational_automation,display_phone_number,health_status,eligibility_for_api_business_global_search,is_official_business_account,is_on_biz_app,is_pin_enabled,is_preverified_number,last_onboarded_time,messaging_limit_tier,name_status,new_certificate,new_display_name,new_name_status,official_business_account,platform_type,quality_score,search_visibility,status,throughput,verified_name")
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 
 	if err != nil {
 		return nil, err
@@ -196,16 +226,24 @@ type CreatePhoneNumberResponse struct {
 }
 
 func (manager *PhoneNumberManager) Create(phoneNumber, verifiedName, countryCode string) (CreatePhoneNumberResponse, error) {
+	return manager.CreateContext(context.Background(), phoneNumber, verifiedName, countryCode)
+}
+
+// CreateContext is like Create but uses ctx for the requests made to the Graph API.
+func (manager *PhoneNumberManager) CreateContext(ctx context.Context, phoneNumber, verifiedName, countryCode string) (CreatePhoneNumberResponse, error) {
 	apiRequest := manager.requester.NewApiRequest(strings.Join([]string{manager.businessAccountId, "/phone_numbers"}, ""), http.MethodPost)
 	apiRequest.AddQueryParam("phone_number", phoneNumber)
 	apiRequest.AddQueryParam("cc", countryCode)
 	apiRequest.AddQueryParam("verified_name", verifiedName)
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return CreatePhoneNumberResponse{}, err
 	}
 	responseToReturn := CreatePhoneNumberResponse{}
 	err = json.Unmarshal([]byte(response), &responseToReturn)
+	if err == nil {
+		manager.logger.DebugContext(ctx, "phone number created", request_client.LogKeyPhoneNumberId, responseToReturn.Id)
+	}
 	return responseToReturn, err
 }
 
@@ -221,12 +259,20 @@ type RequestVerificationCodeResponse struct {
 }
 
 func (manager *PhoneNumberManager) RequestVerificationCode(phoneNumberId string, codeMethod VerifyCodeMethod, languageCode string) (RequestVerificationCodeResponse, error) {
+	return manager.RequestVerificationCodeContext(context.Background(), phoneNumberId, codeMethod, languageCode)
+}
+
+// RequestVerificationCodeContext is like RequestVerificationCode but uses ctx for the requests made to the Graph API.
+func (manager *PhoneNumberManager) RequestVerificationCodeContext(ctx context.Context, phoneNumberId string, codeMethod VerifyCodeMethod, languageCode string) (RequestVerificationCodeResponse, error) {
 	apiRequest := manager.requester.NewApiRequest(strings.Join([]string{phoneNumberId, "request_code"}, "/"), http.MethodPost)
 	apiRequest.AddQueryParam("code_method", string(codeMethod))
 	apiRequest.AddQueryParam("language", languageCode)
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	responseToReturn := RequestVerificationCodeResponse{}
 	json.Unmarshal([]byte(response), &responseToReturn)
+	if err == nil {
+		manager.logger.DebugContext(ctx, "verification code requested", request_client.LogKeyPhoneNumberId, phoneNumberId, "code_method", codeMethod)
+	}
 	return responseToReturn, err
 }
 
@@ -235,11 +281,19 @@ type VerifyCodeResponse struct {
 }
 
 func (manager *PhoneNumberManager) VerifyCode(phoneNumberId, verificationCode string) (VerifyCodeResponse, error) {
+	return manager.VerifyCodeContext(context.Background(), phoneNumberId, verificationCode)
+}
+
+// VerifyCodeContext is like VerifyCode but uses ctx for the requests made to the Graph API.
+func (manager *PhoneNumberManager) VerifyCodeContext(ctx context.Context, phoneNumberId, verificationCode string) (VerifyCodeResponse, error) {
 	apiRequest := manager.requester.NewApiRequest(strings.Join([]string{phoneNumberId, "verify_code"}, "/"), http.MethodPost)
 	apiRequest.AddQueryParam("code", verificationCode)
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	responseToReturn := VerifyCodeResponse{}
 	json.Unmarshal([]byte(response), &responseToReturn)
+	if err == nil {
+		manager.logger.DebugContext(ctx, "phone number verified", request_client.LogKeyPhoneNumberId, phoneNumberId, "success", responseToReturn.Success)
+	}
 	return responseToReturn, err
 }
 
@@ -253,6 +307,11 @@ type GenerateQrCodeResponse struct {
 
 // GenerateQrCode generates a QR code for the specified phone number with the given prefilled message.
 func (manager *PhoneNumberManager) GenerateQrCode(phoneNumber string, prefilledMessage string) (*GenerateQrCodeResponse, error) {
+	return manager.GenerateQrCodeContext(context.Background(), phoneNumber, prefilledMessage)
+}
+
+// GenerateQrCodeContext is like GenerateQrCode but uses ctx for the requests made to the Graph API.
+func (manager *PhoneNumberManager) GenerateQrCodeContext(ctx context.Context, phoneNumber string, prefilledMessage string) (*GenerateQrCodeResponse, error) {
 	apiRequest := manager.requester.NewApiRequest(strings.Join([]string{phoneNumber, "/message_qrdls"}, ""), http.MethodPost)
 	jsonBody, err := json.Marshal(map[string]string{
 		"prefilled_message": prefilledMessage,
@@ -262,12 +321,13 @@ func (manager *PhoneNumberManager) GenerateQrCode(phoneNumber string, prefilledM
 		return nil, err
 	}
 	apiRequest.SetBody(string(jsonBody))
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return nil, err
 	}
 	var responseToReturn GenerateQrCodeResponse
 	json.Unmarshal([]byte(response), &responseToReturn)
+	manager.logger.DebugContext(ctx, "QR code created", request_client.LogKeyPhoneNumberId, phoneNumber, "qr_code", responseToReturn.Code)
 	return &responseToReturn, nil
 }
 
@@ -276,23 +336,36 @@ type GetAllQrCodesResponse struct {
 	Data []GenerateQrCodeResponse `json:"data,omitempty"`
 }
 
-// GetAllQrCodes gets all QR codes for the specified phone number.
+// GetAllQrCodes gets all QR codes for the specified phone number, following the pagination until the last page.
 func (manager *PhoneNumberManager) GetAllQrCodes(phoneNumber string) (*GetAllQrCodesResponse, error) {
-	apiRequest := manager.requester.NewApiRequest(strings.Join([]string{phoneNumber, "/message_qrdls"}, ""), http.MethodGet)
-	response, err := apiRequest.Execute()
+	return manager.GetAllQrCodesContext(context.Background(), phoneNumber)
+}
+
+// GetAllQrCodesContext is like GetAllQrCodes but uses ctx for the requests made to the Graph API.
+func (manager *PhoneNumberManager) GetAllQrCodesContext(ctx context.Context, phoneNumber string) (*GetAllQrCodesResponse, error) {
+	qrCodes, err := manager.PaginateQrCodes(phoneNumber, PaginationOptions{}).Collect(ctx)
 	if err != nil {
 		return nil, err
 	}
+	return &GetAllQrCodesResponse{Data: qrCodes}, nil
+}
 
-	var responseToReturn GetAllQrCodesResponse
-	json.Unmarshal([]byte(response), &responseToReturn)
-	return &responseToReturn, nil
+// PaginateQrCodes returns a paginator over the QR codes of the specified phone number.
+func (manager *PhoneNumberManager) PaginateQrCodes(phoneNumber string, options PaginationOptions) *Paginator[GenerateQrCodeResponse] {
+	return newPaginator[GenerateQrCodeResponse](func() *request_client.ApiRequest {
+		return manager.requester.NewApiRequest(strings.Join([]string{phoneNumber, "/message_qrdls"}, ""), http.MethodGet)
+	}, options)
 }
 
 // GetQrCodeById gets a QR code by its ID for the specified phone number.
 func (manager *PhoneNumberManager) GetQrCodeById(phoneNumber, id string) (*GetAllQrCodesResponse, error) {
+	return manager.GetQrCodeByIdContext(context.Background(), phoneNumber, id)
+}
+
+// GetQrCodeByIdContext is like GetQrCodeById but uses ctx for the requests made to the Graph API.
+func (manager *PhoneNumberManager) GetQrCodeByIdContext(ctx context.Context, phoneNumber, id string) (*GetAllQrCodesResponse, error) {
 	apiRequest := manager.requester.NewApiRequest(strings.Join([]string{phoneNumber, "/message_qrdls", "/", id}, ""), http.MethodDelete)
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -308,19 +381,30 @@ type DeleteQrCodeResponse struct {
 
 // DeleteQrCode deletes a QR code by its ID for the specified phone number.
 func (manager *PhoneNumberManager) DeleteQrCode(phoneNumber, id string) (*DeleteQrCodeResponse, error) {
+	return manager.DeleteQrCodeContext(context.Background(), phoneNumber, id)
+}
+
+// DeleteQrCodeContext is like DeleteQrCode but uses ctx for the requests made to the Graph API.
+func (manager *PhoneNumberManager) DeleteQrCodeContext(ctx context.Context, phoneNumber, id string) (*DeleteQrCodeResponse, error) {
 	apiRequest := manager.requester.NewApiRequest(strings.Join([]string{phoneNumber, "/message_qrdls", "/", id}, ""), http.MethodDelete)
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 
 	if err != nil {
 		return nil, err
 	}
 	var responseToReturn DeleteQrCodeResponse
 	json.Unmarshal([]byte(response), &responseToReturn)
+	manager.logger.DebugContext(ctx, "QR code deleted", request_client.LogKeyPhoneNumberId, phoneNumber, "qr_code", id)
 	return &responseToReturn, nil
 }
 
 // UpdateQrCode updates a QR code by its ID for the specified phone number with the given prefilled message.
 func (manager *PhoneNumberManager) UpdateQrCode(phoneNumber, id, prefilledMessage string) (*GenerateQrCodeResponse, error) {
+	return manager.UpdateQrCodeContext(context.Background(), phoneNumber, id, prefilledMessage)
+}
+
+// UpdateQrCodeContext is like UpdateQrCode but uses ctx for the requests made to the Graph API.
+func (manager *PhoneNumberManager) UpdateQrCodeContext(ctx context.Context, phoneNumber, id, prefilledMessage string) (*GenerateQrCodeResponse, error) {
 	apiRequest := manager.requester.NewApiRequest(strings.Join([]string{phoneNumber, "/message_qrdls"}, ""), http.MethodPost)
 	jsonBody, err := json.Marshal(map[string]string{
 		"prefilled_message": prefilledMessage,
@@ -330,12 +414,13 @@ func (manager *PhoneNumberManager) UpdateQrCode(phoneNumber, id, prefilledMessag
 		return nil, err
 	}
 	apiRequest.SetBody(string(jsonBody))
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	var responseToReturn GenerateQrCodeResponse
 	json.Unmarshal([]byte(response), &responseToReturn)
+	manager.logger.DebugContext(ctx, "QR code updated", request_client.LogKeyPhoneNumberId, phoneNumber, "qr_code", id)
 	return &responseToReturn, nil
 }
@@ -0,0 +1,29 @@
+package manager
+
+import (
+	"context"
+
+	"github.com/gTahidi/wapi.go/internal/request_client"
+)
+
+// RetryPolicy controls how requests failing with throttling, 5xx or network errors are retried.
+// Sending a message is only retried when the Graph API rejected it before processing it, so a message is never sent twice.
+type RetryPolicy = request_client.RetryPolicy
+
+// DefaultRetryPolicy returns the retry policy used when none is configured: 3 attempts with a jittered exponential backoff.
+func DefaultRetryPolicy() RetryPolicy {
+	return request_client.DefaultRetryPolicy()
+}
+
+// NoRetryPolicy returns a retry policy which never retries.
+func NoRetryPolicy() RetryPolicy {
+	return request_client.NoRetryPolicy()
+}
+
+// ContextWithRetryPolicy overrides the retry policy for the calls made with the returned context, for example:
+//
+//	ctx := manager.ContextWithRetryPolicy(ctx, manager.NoRetryPolicy())
+//	response, err := messageManager.SendContext(ctx, message, phoneNumber)
+func ContextWithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return request_client.ContextWithRetryPolicy(ctx, policy)
+}
@@ -1,9 +1,12 @@
 package manager
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gTahidi/wapi.go/internal"
@@ -48,26 +51,45 @@ type WhatsAppBusinessMessageTemplateNode struct {
 // TemplateManager is responsible for managing WhatsApp Business message templates.
 type TemplateManager struct {
 	businessAccountId string
-	apiAccessToken    string
 	requester         *request_client.RequestClient
+	logger            *slog.Logger
 }
 
 // TemplateManagerConfig represents the configuration for creating a new TemplateManager.
 type TemplateManagerConfig struct {
 	BusinessAccountId string
-	ApiAccessToken    string
-	Requester         *request_client.RequestClient
+	// Deprecated: ApiAccessToken is ignored, the requests are sent with the access token of Requester,
+	// use SetAccessToken to give the manager a token of its own.
+	ApiAccessToken string
+	Requester      *request_client.RequestClient
+	// Logger is used to report the created and updated templates, the logger of the requester is used when it is nil.
+	Logger *slog.Logger
 }
 
 // NewTemplateManager creates a new TemplateManager with the given configuration.
 func NewTemplateManager(config *TemplateManagerConfig) *TemplateManager {
 	return &TemplateManager{
-		apiAccessToken:    config.ApiAccessToken,
 		businessAccountId: config.BusinessAccountId,
 		requester:         config.Requester,
+		logger:            managerLogger(config.Logger, config.Requester),
 	}
 }
 
+// SetAccessToken makes the manager send its requests with the given access token, see TokenProvider.
+func (manager *TemplateManager) SetAccessToken(token string) {
+	manager.SetTokenProvider(StaticTokenProvider(token))
+}
+
+// SetTokenProvider makes the manager get the access tokens of its requests from the given provider, see TokenProvider.
+func (manager *TemplateManager) SetTokenProvider(provider TokenProvider) {
+	manager.requester = manager.requester.CloneWithTokenProvider(provider)
+}
+
+// SetLogger sets the logger used to report the created and updated templates, the logger of the requester by default.
+func (manager *TemplateManager) SetLogger(logger *slog.Logger) {
+	manager.logger = request_client.LoggerOrDiscard(logger)
+}
+
 // WhatsAppBusinessTemplatesFetchResponseEdge represents the response structure for fetching templates.
 type WhatsAppBusinessTemplatesFetchResponseEdge struct {
 	Data   []WhatsAppBusinessMessageTemplateNode      `json:"data,omitempty"`
@@ -169,37 +191,60 @@ type TemplateMessageQualityScore struct {
 	Score   int      `json:"score,omitempty"`
 }
 
-// FetchAll fetches all WhatsApp Business message templates.
+// FetchAll fetches all WhatsApp Business message templates, following the pagination until the last page.
 func (manager *TemplateManager) FetchAll() (*WhatsAppBusinessTemplatesFetchResponseEdge, error) {
-	apiRequest := manager.requester.NewApiRequest(strings.Join([]string{manager.businessAccountId, "/", "message_templates"}, ""), http.MethodGet)
+	return manager.FetchAllContext(context.Background())
+}
 
-	fields := []string{
-		"id", "category", "components", "correct_category", "cta_url_link_tracking_opted_out",
-		"language", "library_template_name", "message_send_ttl_seconds", "name", "previous_category",
-		"quality_score", "rejected_reason", "status", "sub_category",
+// FetchAllContext is like FetchAll but uses ctx for the requests made to the Graph API.
+func (manager *TemplateManager) FetchAllContext(ctx context.Context) (*WhatsAppBusinessTemplatesFetchResponseEdge, error) {
+	templates, err := manager.PaginateTemplates(PaginationOptions{PageSize: 1000}).Collect(ctx)
+	if err != nil {
+		return nil, err
 	}
+	return &WhatsAppBusinessTemplatesFetchResponseEdge{Data: templates}, nil
+}
 
-	for _, field := range fields {
-		apiRequest.AddField(request_client.ApiRequestQueryParamField{
-			Name:    field,
-			Filters: map[string]string{},
-		})
-	}
+// PaginateTemplates returns a paginator over the WhatsApp Business message templates.
+func (manager *TemplateManager) PaginateTemplates(options PaginationOptions) *Paginator[WhatsAppBusinessMessageTemplateNode] {
+	return newPaginator[WhatsAppBusinessMessageTemplateNode](func() *request_client.ApiRequest {
+		apiRequest := manager.requester.NewApiRequest(strings.Join([]string{manager.businessAccountId, "/", "message_templates"}, ""), http.MethodGet)
 
-	apiRequest.AddQueryParam("limit", "1000")
+		fields := []string{
+			"id", "category", "components", "correct_category", "cta_url_link_tracking_opted_out",
+			"language", "library_template_name", "message_send_ttl_seconds", "name", "previous_category",
+			"quality_score", "rejected_reason", "status", "sub_category",
+		}
+
+		for _, field := range fields {
+			apiRequest.AddField(request_client.ApiRequestQueryParamField{
+				Name:    field,
+				Filters: map[string]string{},
+			})
+		}
+		return apiRequest
+	}, options)
+}
 
-	response, err := apiRequest.Execute()
+// Fetch fetches a single WhatsApp Business message template by its ID.
+func (manager *TemplateManager) Fetch(Id string) (*WhatsAppBusinessMessageTemplateNode, error) {
+	return manager.FetchContext(context.Background(), Id)
+}
+
+// FetchContext is like Fetch but uses ctx for the requests made to the Graph API.
+func (manager *TemplateManager) FetchContext(ctx context.Context, Id string) (*WhatsAppBusinessMessageTemplateNode, error) {
+	apiRequest := manager.newFetchRequest(Id)
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return nil, err
 	}
-
-	var responseToReturn WhatsAppBusinessTemplatesFetchResponseEdge
+	var responseToReturn WhatsAppBusinessMessageTemplateNode
 	json.Unmarshal([]byte(response), &responseToReturn)
 	return &responseToReturn, nil
 }
 
-// Fetch fetches a single WhatsApp Business message template by its ID.
-func (manager *TemplateManager) Fetch(Id string) (*WhatsAppBusinessMessageTemplateNode, error) {
+// newFetchRequest builds the request fetching a single template.
+func (manager *TemplateManager) newFetchRequest(Id string) *request_client.ApiRequest {
 	apiRequest := manager.requester.NewApiRequest(strings.Join([]string{Id}, ""), http.MethodGet)
 	fields := []string{
 		"id", "category", "components", "correct_category", "cta_url_link_tracking_opted_out",
@@ -212,13 +257,34 @@ func (manager *TemplateManager) Fetch(Id string) (*WhatsAppBusinessMessageTempla
 			Filters: map[string]string{},
 		})
 	}
-	response, err := apiRequest.Execute()
-	if err != nil {
-		return nil, err
+	return apiRequest
+}
+
+// BatchFetch fetches the templates with the given IDs in Graph API batch requests of up to 50 templates.
+// The returned templates are in the order of the IDs, with nil for the ones which could not be fetched,
+// and the map holds the error of each of those by index.
+func (manager *TemplateManager) BatchFetch(ids []string) ([]*WhatsAppBusinessMessageTemplateNode, map[int]error) {
+	return manager.BatchFetchContext(context.Background(), ids)
+}
+
+// BatchFetchContext is like BatchFetch but uses ctx for the requests made to the Graph API.
+func (manager *TemplateManager) BatchFetchContext(ctx context.Context, ids []string) ([]*WhatsAppBusinessMessageTemplateNode, map[int]error) {
+	requests := make([]*request_client.ApiRequest, 0, len(ids))
+	for _, id := range ids {
+		requests = append(requests, manager.newFetchRequest(id))
 	}
-	var responseToReturn WhatsAppBusinessMessageTemplateNode
-	json.Unmarshal([]byte(response), &responseToReturn)
-	return &responseToReturn, nil
+
+	templates := make([]*WhatsAppBusinessMessageTemplateNode, len(ids))
+	errs := make(map[int]error)
+	for i, response := range executeBatched(ctx, manager.requester, requests) {
+		var template WhatsAppBusinessMessageTemplateNode
+		if err := response.Decode(&template); err != nil {
+			errs[i] = err
+			continue
+		}
+		templates[i] = &template
+	}
+	return templates, errs
 }
 
 // WhatsappMessageTemplateButtonCreateRequestBody represents the request body for creating a button.
@@ -295,6 +361,11 @@ type MessageTemplateCreationResponse struct {
 
 // Create sends a creation request for a message template.
 func (manager *TemplateManager) Create(body WhatsappMessageTemplateCreateRequestBody) (*MessageTemplateCreationResponse, error) {
+	return manager.CreateContext(context.Background(), body)
+}
+
+// CreateContext is like Create but uses ctx for the requests made to the Graph API.
+func (manager *TemplateManager) CreateContext(ctx context.Context, body WhatsappMessageTemplateCreateRequestBody) (*MessageTemplateCreationResponse, error) {
 	// Pre-validate catalog and multi-product message buttons
 	if err := validateCatalogAndMPMButtons(body.Components); err != nil {
 		return nil, err
@@ -305,13 +376,14 @@ func (manager *TemplateManager) Create(body WhatsappMessageTemplateCreateRequest
 		return nil, err
 	}
 	apiRequest.SetBody(string(jsonBody))
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	var responseToReturn MessageTemplateCreationResponse
 	json.Unmarshal([]byte(response), &responseToReturn)
+	manager.logger.DebugContext(ctx, "template created", "template_id", responseToReturn.Id, "template_name", body.Name, "status", responseToReturn.Status)
 	return &responseToReturn, nil
 }
 
@@ -324,6 +396,11 @@ type WhatsAppBusinessAccountMessageTemplateUpdateRequestBody struct {
 
 // Update sends an update request for a template.
 func (manager *TemplateManager) Update(templateId string, updates WhatsAppBusinessAccountMessageTemplateUpdateRequestBody) (*MessageTemplateCreationResponse, error) {
+	return manager.UpdateContext(context.Background(), templateId, updates)
+}
+
+// UpdateContext is like Update but uses ctx for the requests made to the Graph API.
+func (manager *TemplateManager) UpdateContext(ctx context.Context, templateId string, updates WhatsAppBusinessAccountMessageTemplateUpdateRequestBody) (*MessageTemplateCreationResponse, error) {
 	// Pre-validate catalog and multi-product message buttons
 	if len(updates.Components) > 0 {
 		if err := validateCatalogAndMPMButtons(updates.Components); err != nil {
@@ -331,18 +408,20 @@ func (manager *TemplateManager) Update(templateId string, updates WhatsAppBusine
 		}
 	}
 	apiRequest := manager.requester.NewApiRequest(strings.Join([]string{templateId}, ""), http.MethodPost)
+	apiRequest.SetIdempotent(true)
 	jsonBody, err := json.Marshal(updates)
 	if err != nil {
 		return nil, err
 	}
 	apiRequest.SetBody(string(jsonBody))
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	var responseToReturn MessageTemplateCreationResponse
 	json.Unmarshal([]byte(response), &responseToReturn)
+	manager.logger.DebugContext(ctx, "template updated", "template_id", templateId, "status", responseToReturn.Status)
 	return &responseToReturn, nil
 }
 
@@ -386,10 +465,15 @@ type TemplateMigrationResponse struct {
 
 // MigrateFromOtherBusinessAccount migrates templates from another business account.
 func (manager *TemplateManager) MigrateFromOtherBusinessAccount(sourcePageNumber int, sourceWabaId int) (*TemplateMigrationResponse, error) {
+	return manager.MigrateFromOtherBusinessAccountContext(context.Background(), sourcePageNumber, sourceWabaId)
+}
+
+// MigrateFromOtherBusinessAccountContext is like MigrateFromOtherBusinessAccount but uses ctx for the requests made to the Graph API.
+func (manager *TemplateManager) MigrateFromOtherBusinessAccountContext(ctx context.Context, sourcePageNumber int, sourceWabaId int) (*TemplateMigrationResponse, error) {
 	apiRequest := manager.requester.NewApiRequest(strings.Join([]string{manager.businessAccountId, "migrate_message_templates"}, "/"), http.MethodGet)
-	apiRequest.AddQueryParam("page_number", string(sourcePageNumber))
-	apiRequest.AddQueryParam("source_waba_id", string(sourceWabaId))
-	response, err := apiRequest.Execute()
+	apiRequest.AddQueryParam("page_number", strconv.Itoa(sourcePageNumber))
+	apiRequest.AddQueryParam("source_waba_id", strconv.Itoa(sourceWabaId))
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return nil, err
 	}
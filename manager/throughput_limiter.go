@@ -0,0 +1,297 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ThroughputLimitPolicy decides what happens to a message which would exceed the throughput limits.
+type ThroughputLimitPolicy int
+
+const (
+	// ThroughputLimitPolicyBlock makes the send wait until the message can go out, or the context is done.
+	ThroughputLimitPolicyBlock ThroughputLimitPolicy = iota
+	// ThroughputLimitPolicyFailFast makes the send return a *ThroughputLimitError straight away.
+	ThroughputLimitPolicyFailFast
+)
+
+// Messages per second allowed for each throughput level of a phone number,
+// see https://developers.facebook.com/docs/whatsapp/cloud-api/overview#throughput
+const (
+	StandardThroughputMessagesPerSecond = 80
+	HighThroughputMessagesPerSecond     = 1000
+)
+
+const (
+	// DefaultRecipientInterval is the pace of messages sent to a single recipient, which the
+	// pair rate limit (error 131056) allows to be exceeded by a burst of DefaultRecipientBurst messages.
+	DefaultRecipientInterval = 6 * time.Second
+	DefaultRecipientBurst    = 45
+)
+
+// ThroughputLimiterConfig configures a ThroughputLimiter, all the fields are optional.
+type ThroughputLimiterConfig struct {
+	// MessagesPerSecond is the rate of each phone number which has no rate of its own, StandardThroughputMessagesPerSecond by default.
+	MessagesPerSecond float64
+	// Burst is the number of messages a phone number can send at once, MessagesPerSecond by default.
+	Burst int
+	// RecipientInterval is the pace of the messages sent to the same recipient, DefaultRecipientInterval by default.
+	// A negative value disables the pacing per recipient.
+	RecipientInterval time.Duration
+	// RecipientBurst is the number of messages which can be sent at once to the same recipient, DefaultRecipientBurst by default.
+	RecipientBurst int
+	// Policy decides whether a send waits for the limiter or fails fast.
+	Policy ThroughputLimitPolicy
+}
+
+// ThroughputLimitError is returned when a message is rejected by a ThroughputLimiter using ThroughputLimitPolicyFailFast.
+type ThroughputLimitError struct {
+	PhoneNumberId string
+	Recipient     string
+	// RetryAfter is how long to wait before the message can be sent.
+	RetryAfter time.Duration
+}
+
+func (e *ThroughputLimitError) Error() string {
+	return fmt.Sprintf("throughput limit of phone number %s reached for recipient %s, retry after %s", e.PhoneNumberId, e.Recipient, e.RetryAfter)
+}
+
+// PhoneNumberThroughputState is a snapshot of the limiter state of a phone number, meant for monitoring.
+type PhoneNumberThroughputState struct {
+	PhoneNumberId     string
+	MessagesPerSecond float64
+	Burst             int
+	// AvailableTokens is the number of messages which can be sent right away, negative when sends are queued.
+	AvailableTokens float64
+	// Waiting is the number of sends currently blocked by the limiter.
+	Waiting int
+	// Allowed and Rejected count the sends let through and the ones rejected by the fail fast policy.
+	Allowed  uint64
+	Rejected uint64
+	// TrackedRecipients is the number of recipients currently paced.
+	TrackedRecipients int
+}
+
+// tokenBucket is a token bucket refilled continuously at rate tokens per second, up to burst tokens.
+// The tokens go negative when sends reserve tokens in advance and wait for them.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: now}
+}
+
+func (bucket *tokenBucket) advance(now time.Time) {
+	if now.After(bucket.last) {
+		bucket.tokens = math.Min(bucket.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*bucket.rate)
+		bucket.last = now
+	}
+}
+
+// delay returns how long to wait for a token to be available.
+func (bucket *tokenBucket) delay(now time.Time) time.Duration {
+	bucket.advance(now)
+	if bucket.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - bucket.tokens) / bucket.rate * float64(time.Second))
+}
+
+// full reports whether the bucket holds as many tokens as it can, i.e. it has not been used for a while.
+func (bucket *tokenBucket) full(now time.Time) bool {
+	bucket.advance(now)
+	return bucket.tokens >= bucket.burst
+}
+
+type phoneNumberLimiter struct {
+	bucket     *tokenBucket
+	recipients map[string]*tokenBucket
+	lastSweep  time.Time
+	waiting    int
+	allowed    uint64
+	rejected   uint64
+}
+
+// ThroughputLimiter paces the messages sent by each phone number, so that bursts of sends do not hit the
+// throughput of the number (error 130429) or the pair rate limit of a recipient (error 131056).
+// A single limiter can be shared by all the message managers of an application.
+type ThroughputLimiter struct {
+	config       ThroughputLimiterConfig
+	mutex        sync.Mutex
+	phoneNumbers map[string]*phoneNumberLimiter
+}
+
+// NewThroughputLimiter creates a new instance of ThroughputLimiter.
+func NewThroughputLimiter(config ThroughputLimiterConfig) *ThroughputLimiter {
+	if config.MessagesPerSecond <= 0 {
+		config.MessagesPerSecond = StandardThroughputMessagesPerSecond
+	}
+	if config.Burst <= 0 {
+		config.Burst = int(math.Max(1, config.MessagesPerSecond))
+	}
+	if config.RecipientInterval == 0 {
+		config.RecipientInterval = DefaultRecipientInterval
+	}
+	if config.RecipientBurst <= 0 {
+		config.RecipientBurst = DefaultRecipientBurst
+	}
+	return &ThroughputLimiter{
+		config:       config,
+		phoneNumbers: map[string]*phoneNumberLimiter{},
+	}
+}
+
+// MessagesPerSecondForThroughput returns the rate matching the throughput level of a phone number,
+// or StandardThroughputMessagesPerSecond when the level is unknown.
+func MessagesPerSecondForThroughput(throughput *WabaThroughput) float64 {
+	if throughput != nil && strings.EqualFold(throughput.Level, "HIGH") {
+		return HighThroughputMessagesPerSecond
+	}
+	return StandardThroughputMessagesPerSecond
+}
+
+// SetPhoneNumberRate sets the rate of a single phone number, overriding the one of the limiter config.
+func (limiter *ThroughputLimiter) SetPhoneNumberRate(phoneNumberId string, messagesPerSecond float64, burst int) {
+	if messagesPerSecond <= 0 {
+		messagesPerSecond = limiter.config.MessagesPerSecond
+	}
+	if burst <= 0 {
+		burst = int(math.Max(1, messagesPerSecond))
+	}
+
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	now := time.Now()
+	phoneNumber := limiter.phoneNumber(phoneNumberId, now)
+	phoneNumber.bucket.advance(now)
+	phoneNumber.bucket.rate = messagesPerSecond
+	phoneNumber.bucket.burst = float64(burst)
+	phoneNumber.bucket.tokens = math.Min(phoneNumber.bucket.tokens, phoneNumber.bucket.burst)
+}
+
+// SetPhoneNumberThroughput sets the rate of a phone number from its throughput level,
+// as returned by PhoneNumberManager.Fetch.
+func (limiter *ThroughputLimiter) SetPhoneNumberThroughput(phoneNumberId string, throughput *WabaThroughput) {
+	limiter.SetPhoneNumberRate(phoneNumberId, MessagesPerSecondForThroughput(throughput), 0)
+}
+
+// phoneNumber returns the limiter of a phone number, creating it if needed. The mutex must be held.
+func (limiter *ThroughputLimiter) phoneNumber(phoneNumberId string, now time.Time) *phoneNumberLimiter {
+	phoneNumber, ok := limiter.phoneNumbers[phoneNumberId]
+	if !ok {
+		phoneNumber = &phoneNumberLimiter{
+			bucket:     newTokenBucket(limiter.config.MessagesPerSecond, limiter.config.Burst, now),
+			recipients: map[string]*tokenBucket{},
+			lastSweep:  now,
+		}
+		limiter.phoneNumbers[phoneNumberId] = phoneNumber
+	}
+	return phoneNumber
+}
+
+// Wait waits until a message can be sent from the given phone number to the given recipient, or returns
+// a *ThroughputLimitError when the limiter uses ThroughputLimitPolicyFailFast. An empty recipient is not paced.
+func (limiter *ThroughputLimiter) Wait(ctx context.Context, phoneNumberId, recipient string) error {
+	limiter.mutex.Lock()
+	now := time.Now()
+	phoneNumber := limiter.phoneNumber(phoneNumberId, now)
+	limiter.sweepRecipients(phoneNumber, now)
+
+	var recipientBucket *tokenBucket
+	if recipient != "" && limiter.config.RecipientInterval > 0 {
+		recipientBucket = phoneNumber.recipients[recipient]
+		if recipientBucket == nil {
+			recipientBucket = newTokenBucket(float64(time.Second)/float64(limiter.config.RecipientInterval), limiter.config.RecipientBurst, now)
+			phoneNumber.recipients[recipient] = recipientBucket
+		}
+	}
+
+	delay := phoneNumber.bucket.delay(now)
+	if recipientBucket != nil {
+		delay = max(delay, recipientBucket.delay(now))
+	}
+
+	if delay > 0 && limiter.config.Policy == ThroughputLimitPolicyFailFast {
+		phoneNumber.rejected++
+		limiter.mutex.Unlock()
+		return &ThroughputLimitError{PhoneNumberId: phoneNumberId, Recipient: recipient, RetryAfter: delay}
+	}
+
+	// reserve the tokens now, so that the sends waiting concurrently queue up behind each other
+	phoneNumber.bucket.tokens--
+	if recipientBucket != nil {
+		recipientBucket.tokens--
+	}
+	if delay == 0 {
+		phoneNumber.allowed++
+		limiter.mutex.Unlock()
+		return nil
+	}
+	phoneNumber.waiting++
+	limiter.mutex.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		limiter.mutex.Lock()
+		phoneNumber.waiting--
+		phoneNumber.allowed++
+		limiter.mutex.Unlock()
+		return nil
+	case <-ctx.Done():
+		// give the reserved tokens back
+		limiter.mutex.Lock()
+		phoneNumber.waiting--
+		phoneNumber.bucket.tokens++
+		if recipientBucket != nil {
+			recipientBucket.tokens++
+		}
+		limiter.mutex.Unlock()
+		return ctx.Err()
+	}
+}
+
+// sweepRecipients forgets the recipients which have not been messaged for long enough for their bucket to be full.
+// The mutex must be held.
+func (limiter *ThroughputLimiter) sweepRecipients(phoneNumber *phoneNumberLimiter, now time.Time) {
+	if now.Sub(phoneNumber.lastSweep) < time.Minute {
+		return
+	}
+	phoneNumber.lastSweep = now
+	for recipient, bucket := range phoneNumber.recipients {
+		if bucket.full(now) {
+			delete(phoneNumber.recipients, recipient)
+		}
+	}
+}
+
+// State returns the state of every phone number known to the limiter.
+func (limiter *ThroughputLimiter) State() []PhoneNumberThroughputState {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	now := time.Now()
+	states := make([]PhoneNumberThroughputState, 0, len(limiter.phoneNumbers))
+	for phoneNumberId, phoneNumber := range limiter.phoneNumbers {
+		phoneNumber.bucket.advance(now)
+		states = append(states, PhoneNumberThroughputState{
+			PhoneNumberId:     phoneNumberId,
+			MessagesPerSecond: phoneNumber.bucket.rate,
+			Burst:             int(phoneNumber.bucket.burst),
+			AvailableTokens:   phoneNumber.bucket.tokens,
+			Waiting:           phoneNumber.waiting,
+			Allowed:           phoneNumber.allowed,
+			Rejected:          phoneNumber.rejected,
+			TrackedRecipients: len(phoneNumber.recipients),
+		})
+	}
+	return states
+}
@@ -0,0 +1,132 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketDelay(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	tests := []struct {
+		name  string
+		rate  float64
+		burst int
+		taken int
+		after time.Duration
+		want  time.Duration
+	}{
+		{"full bucket", 10, 5, 0, 0, 0},
+		{"last token", 10, 5, 4, 0, 0},
+		{"empty bucket", 10, 5, 5, 0, 100 * time.Millisecond},
+		{"overdrawn bucket", 10, 5, 7, 0, 300 * time.Millisecond},
+		{"partly refilled", 10, 5, 5, 50 * time.Millisecond, 50 * time.Millisecond},
+		{"refilled", 10, 5, 5, 100 * time.Millisecond, 0},
+		{"slow rate", 0.5, 1, 1, 0, 2 * time.Second},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bucket := newTokenBucket(test.rate, test.burst, start)
+			bucket.tokens -= float64(test.taken)
+			got := bucket.delay(start.Add(test.after))
+			if diff := got - test.want; diff < -time.Microsecond || diff > time.Microsecond {
+				t.Errorf("delay() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTokenBucketRefillIsCappedByBurst(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	bucket := newTokenBucket(10, 5, start)
+	bucket.tokens = 0
+	if bucket.full(start.Add(100 * time.Millisecond)) {
+		t.Fatal("bucket full after refilling a single token")
+	}
+	if !bucket.full(start.Add(time.Hour)) {
+		t.Fatal("bucket not full after an hour")
+	}
+	if bucket.tokens != 5 {
+		t.Fatalf("got %v tokens, want the burst of 5", bucket.tokens)
+	}
+	// time going backwards does not refill the bucket
+	bucket.tokens = 0
+	bucket.advance(start)
+	if bucket.tokens != 0 {
+		t.Fatalf("got %v tokens after going back in time, want 0", bucket.tokens)
+	}
+}
+
+func TestThroughputLimiterFailFast(t *testing.T) {
+	limiter := NewThroughputLimiter(ThroughputLimiterConfig{
+		MessagesPerSecond: 1,
+		Burst:             2,
+		RecipientInterval: -1,
+		Policy:            ThroughputLimitPolicyFailFast,
+	})
+	ctx := context.Background()
+	for i := range 2 {
+		if err := limiter.Wait(ctx, "phone", "recipient"); err != nil {
+			t.Fatalf("send %d: %v", i, err)
+		}
+	}
+	err := limiter.Wait(ctx, "phone", "recipient")
+	var limitError *ThroughputLimitError
+	if !errors.As(err, &limitError) {
+		t.Fatalf("got error %v, want a *ThroughputLimitError", err)
+	}
+	if limitError.RetryAfter <= 0 || limitError.RetryAfter > time.Second {
+		t.Errorf("got RetryAfter %v, want at most a second", limitError.RetryAfter)
+	}
+	// the other phone numbers have buckets of their own
+	if err := limiter.Wait(ctx, "other phone", "recipient"); err != nil {
+		t.Fatalf("send from another phone number: %v", err)
+	}
+
+	states := map[string]PhoneNumberThroughputState{}
+	for _, state := range limiter.State() {
+		states[state.PhoneNumberId] = state
+	}
+	if state := states["phone"]; state.Allowed != 2 || state.Rejected != 1 {
+		t.Errorf("got %d allowed and %d rejected sends, want 2 and 1", state.Allowed, state.Rejected)
+	}
+}
+
+func TestThroughputLimiterPacesRecipients(t *testing.T) {
+	limiter := NewThroughputLimiter(ThroughputLimiterConfig{
+		MessagesPerSecond: 1000,
+		RecipientInterval: time.Hour,
+		RecipientBurst:    1,
+		Policy:            ThroughputLimitPolicyFailFast,
+	})
+	ctx := context.Background()
+	if err := limiter.Wait(ctx, "phone", "recipient"); err != nil {
+		t.Fatal(err)
+	}
+	if err := limiter.Wait(ctx, "phone", "recipient"); err == nil {
+		t.Fatal("second message to the same recipient was not paced")
+	}
+	if err := limiter.Wait(ctx, "phone", "other recipient"); err != nil {
+		t.Fatalf("message to another recipient: %v", err)
+	}
+	if err := limiter.Wait(ctx, "phone", ""); err != nil {
+		t.Fatalf("message without recipient: %v", err)
+	}
+}
+
+func TestThroughputLimiterBlockGivesTokensBackOnCancel(t *testing.T) {
+	limiter := NewThroughputLimiter(ThroughputLimiterConfig{MessagesPerSecond: 0.001, Burst: 1, RecipientInterval: -1})
+	if err := limiter.Wait(context.Background(), "phone", "recipient"); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx, "phone", "recipient"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+	state := limiter.State()[0]
+	if state.Waiting != 0 || state.AvailableTokens < -0.01 || state.AvailableTokens > 0.01 {
+		t.Errorf("got %d waiting sends and %v tokens, want 0 and 0", state.Waiting, state.AvailableTokens)
+	}
+}
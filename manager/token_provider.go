@@ -0,0 +1,44 @@
+package manager
+
+import "github.com/gTahidi/wapi.go/internal/request_client"
+
+// TokenProvider provides the access token of every request sent to the Graph API, so that rotated
+// tokens are picked up without rebuilding the client.
+//
+// The managers created by a client share its token provider, so that the SetAccessToken and SetTokenProvider
+// methods of the client apply to all of them. The SetAccessToken and SetTokenProvider methods of a manager
+// give it a copy of the request client with a provider of its own, for an account with its own token: the other
+// managers keep their token, and later changes of the token of the client no longer apply to this manager.
+// They are meant to be called while setting a manager up, not concurrently with its requests; a token which
+// rotates is better served by a provider returning the current token, like CachingTokenProvider.
+type TokenProvider = request_client.TokenProvider
+
+// TokenInvalidator is implemented by the token providers which cache their token, the cached token
+// is invalidated and the request sent again once when the Graph API rejects the token as expired.
+type TokenInvalidator = request_client.TokenInvalidator
+
+// StaticTokenProvider provides the same access token for every request.
+type StaticTokenProvider = request_client.StaticTokenProvider
+
+// TokenProviderFunc adapts a function to the TokenProvider interface.
+type TokenProviderFunc = request_client.TokenProviderFunc
+
+// TokenFetchFunc fetches a fresh access token and the time it expires at, a zero time when it is unknown.
+type TokenFetchFunc = request_client.TokenFetchFunc
+
+// CachingTokenProviderConfig configures a CachingTokenProvider.
+type CachingTokenProviderConfig = request_client.CachingTokenProviderConfig
+
+// CachingTokenProvider caches the token returned by a fetch function until it is about to expire, for example:
+//
+//	provider := manager.NewCachingTokenProvider(manager.CachingTokenProviderConfig{
+//		Fetch: func(ctx context.Context) (string, time.Time, error) {
+//			return vault.SystemUserToken(ctx)
+//		},
+//	})
+type CachingTokenProvider = request_client.CachingTokenProvider
+
+// NewCachingTokenProvider creates a new instance of CachingTokenProvider.
+func NewCachingTokenProvider(config CachingTokenProviderConfig) *CachingTokenProvider {
+	return request_client.NewCachingTokenProvider(config)
+}
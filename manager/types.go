@@ -1,5 +1,7 @@
 package manager
 
+import "encoding/json"
+
 type NotificationReasonEnum string
 
 const (
@@ -138,9 +140,19 @@ type NotificationPayloadInteractionMessageSchemaType struct {
 		Type                                                  InteractiveNotificationTypeEnum `json:"type"`
 		NotificationPayloadButtonInteractionMessageSchemaType `json:",inline,omitempty"`
 		NotificationPayloadListInteractionMessageSchemaType   `json:",inline,omitempty"`
+		NotificationPayloadNfmReplyMessageSchemaType          `json:",inline,omitempty"`
 	} `json:"interactive,omitempty"`
 }
 
+// NotificationPayloadNfmReplyMessageSchemaType is the reply sent when a user completes a flow.
+type NotificationPayloadNfmReplyMessageSchemaType struct {
+	NfmReply struct {
+		Name         string `json:"name"`
+		Body         string `json:"body"`
+		ResponseJson string `json:"response_json"` // * JSON object with the flow_token and the data of the completed flow
+	} `json:"nfm_reply,omitempty"`
+}
+
 type NotificationPayloadButtonInteractionMessageSchemaType struct {
 	ButtonReply struct {
 		Id    string `json:"id"`
@@ -166,7 +178,59 @@ type NotificationPayloadLocationMessageSchemaType struct {
 }
 
 type NotificationPayloadContactMessageSchemaType struct {
-	Contacts []Contact `json:"contacts"`
+	Contacts []SharedContact `json:"contacts"`
+}
+
+// SharedContact is a contact card shared by a user in a contacts message.
+type SharedContact struct {
+	Name      SharedContactName      `json:"name"`
+	Org       SharedContactOrg       `json:"org,omitempty"`
+	Addresses []SharedContactAddress `json:"addresses,omitempty"`
+	Urls      []SharedContactUrl     `json:"urls,omitempty"`
+	Emails    []SharedContactEmail   `json:"emails,omitempty"`
+	Phones    []SharedContactPhone   `json:"phones,omitempty"`
+	Birthday  string                 `json:"birthday,omitempty"` // * YYYY-MM-DD
+}
+
+type SharedContactName struct {
+	FormattedName string `json:"formatted_name"`
+	FirstName     string `json:"first_name,omitempty"`
+	LastName      string `json:"last_name,omitempty"`
+	MiddleName    string `json:"middle_name,omitempty"`
+	Suffix        string `json:"suffix,omitempty"`
+	Prefix        string `json:"prefix,omitempty"`
+}
+
+type SharedContactOrg struct {
+	Company    string `json:"company,omitempty"`
+	Department string `json:"department,omitempty"`
+	Title      string `json:"title,omitempty"`
+}
+
+type SharedContactAddress struct {
+	Street      string `json:"street,omitempty"`
+	City        string `json:"city,omitempty"`
+	State       string `json:"state,omitempty"`
+	Zip         string `json:"zip,omitempty"`
+	Country     string `json:"country,omitempty"`
+	CountryCode string `json:"country_code,omitempty"`
+	Type        string `json:"type,omitempty"` // * HOME or WORK
+}
+
+type SharedContactUrl struct {
+	Url  string `json:"url"`
+	Type string `json:"type,omitempty"` // * HOME or WORK
+}
+
+type SharedContactEmail struct {
+	Email string `json:"email"`
+	Type  string `json:"type,omitempty"` // * HOME or WORK
+}
+
+type SharedContactPhone struct {
+	Phone string `json:"phone"`
+	WaId  string `json:"wa_id,omitempty"` // * present when the phone number is on WhatsApp
+	Type  string `json:"type,omitempty"`  // * CELL, MAIN, IPHONE, HOME or WORK
 }
 
 type NotificationMessageTypeEnum string
@@ -184,6 +248,7 @@ const (
 	NotificationMessageTypeReaction    NotificationMessageTypeEnum = "reaction"
 	NotificationMessageTypeInteractive NotificationMessageTypeEnum = "interactive"
 	NotificationMessageTypeUnknown     NotificationMessageTypeEnum = "unknown"
+	NotificationMessageTypeUnsupported NotificationMessageTypeEnum = "unsupported"
 	NotificationMessageTypeLocation    NotificationMessageTypeEnum = "location"
 	NotificationMessageTypeContacts    NotificationMessageTypeEnum = "contacts"
 )
@@ -193,12 +258,15 @@ type InteractiveNotificationTypeEnum string
 const (
 	NotificationTypeButtonReply InteractiveNotificationTypeEnum = "button_reply"
 	NotificationTypeListReply   InteractiveNotificationTypeEnum = "list_reply"
+	NotificationTypeNfmReply    InteractiveNotificationTypeEnum = "nfm_reply"
 )
 
 type AdInteractionSourceTypeEnum string
 
 const (
 	AdInteractionSourceTypeUnknown AdInteractionSourceTypeEnum = "unknown"
+	AdInteractionSourceTypeAd      AdInteractionSourceTypeEnum = "ad"
+	AdInteractionSourceTypePost    AdInteractionSourceTypeEnum = "post"
 	// Add other ad interaction source types
 )
 
@@ -233,9 +301,9 @@ type WhatsappApiNotificationPayloadSchemaType struct {
 }
 
 type Entry struct {
-	Id      string   `json:"id"`
-	Changes []Change `json:"changes"`
-	Time    *string  `json:"time"`
+	Id      string       `json:"id"`
+	Changes []Change     `json:"changes"`
+	Time    *json.Number `json:"time"` // * unix timestamp, sent as a number for the business account fields
 }
 
 type WebhookFieldEnum string
@@ -245,13 +313,14 @@ const (
 	WebhookFieldEnumMessages               WebhookFieldEnum = "messages"
 	WebhookFieldEnumSecurity               WebhookFieldEnum = "security"
 	WebhookFieldEnumAccountUpdate          WebhookFieldEnum = "account_update"
-	WebhookFieldEnumAccountReview          WebhookFieldEnum = "account_review"
-	WebhookFieldEnumBusinessCapability     WebhookFieldEnum = "business_capability"
-	WebhookFieldEnumMessageTemplateQuality WebhookFieldEnum = "message_template_quality"
-	WebhookFieldEnumMessageTemplateStatus  WebhookFieldEnum = "message_template_status"
-	WebhookFieldEnumPhoneNumberName        WebhookFieldEnum = "phone_number_name"
-	WebhookFieldEnumPhoneNumberQuality     WebhookFieldEnum = "phone_number_quality"
-	WebhookFieldEnumTemplateCategoryUpdate WebhookFieldEnum = "template_category"
+	WebhookFieldEnumAccountReview          WebhookFieldEnum = "account_review_update"
+	WebhookFieldEnumBusinessCapability     WebhookFieldEnum = "business_capability_update"
+	WebhookFieldEnumMessageTemplateQuality WebhookFieldEnum = "message_template_quality_update"
+	WebhookFieldEnumMessageTemplateStatus  WebhookFieldEnum = "message_template_status_update"
+	WebhookFieldEnumPhoneNumberName        WebhookFieldEnum = "phone_number_name_update"
+	WebhookFieldEnumPhoneNumberQuality     WebhookFieldEnum = "phone_number_quality_update"
+	WebhookFieldEnumTemplateCategoryUpdate WebhookFieldEnum = "template_category_update"
+	WebhookFieldEnumFlows                  WebhookFieldEnum = "flows"
 )
 
 type TemplateMessageStatusUpdateEventEnum string
@@ -269,7 +338,8 @@ type TemplateMessageStatusUpdateDisableInfo struct {
 }
 
 type TemplateMessageStatusUpdateOtherInfo struct {
-	Title string `json:"title"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
 }
 
 type TemplateMessageRejectionReasonEnum string
@@ -283,13 +353,13 @@ const (
 )
 
 type TemplateStatusUpdateValue struct {
-	Event                   TemplateMessageStatusUpdateEventEnum   `json:"event"`
-	MessageTemplateId       int64                                  `json:"message_template_id"`
-	MessageTemplateName     string                                 `json:"message_template_name"`
-	MessageTemplateLanguage string                                 `json:"message_template_language"`
-	Reason                  TemplateMessageRejectionReasonEnum     `json:"reason"`
-	DisableInfo             TemplateMessageStatusUpdateDisableInfo `json:"disable_info,omitempty"`
-	OtherInfo               TemplateMessageStatusUpdateOtherInfo   `json:"other_info,omitempty"`
+	Event                   TemplateMessageStatusUpdateEventEnum    `json:"event"`
+	MessageTemplateId       int64                                   `json:"message_template_id"`
+	MessageTemplateName     string                                  `json:"message_template_name"`
+	MessageTemplateLanguage string                                  `json:"message_template_language"`
+	Reason                  TemplateMessageRejectionReasonEnum      `json:"reason"`
+	DisableInfo             *TemplateMessageStatusUpdateDisableInfo `json:"disable_info,omitempty"`
+	OtherInfo               *TemplateMessageStatusUpdateOtherInfo   `json:"other_info,omitempty"`
 }
 
 type TemplateCategoryUpdateValue struct {
@@ -331,6 +401,7 @@ type PhoneNumberQualityUpdateValue struct {
 	DisplayPhoneNumber string                                   `json:"display_phone_number"`
 	Event              string                                   `json:"event"`
 	CurrentLimit       PhoneNumberQualityUpdateCurrentLimitEnum `json:"current_limit"`
+	OldLimit           PhoneNumberQualityUpdateCurrentLimitEnum `json:"old_limit,omitempty"`
 }
 
 type AccountAlertSeverityEnum string
@@ -375,8 +446,11 @@ const (
 )
 
 type AccountUpdateValue struct {
-	PhoneNumber string                 `json:"phone_number,omitempty"`
-	Event       AccountUpdateEventEnum `json:"event"`
+	PhoneNumber     string                         `json:"phone_number,omitempty"`
+	Event           AccountUpdateEventEnum         `json:"event"`
+	BanInfo         *AccountUpdateBanInfo          `json:"ban_info,omitempty"`
+	ViolationInfo   *AccountUpdateViolationInfo    `json:"violation_info,omitempty"`
+	RestrictionInfo []AccountUpdateRestrictionInfo `json:"restriction_info,omitempty"`
 }
 
 type AccountReviewUpdateValue struct {
@@ -384,8 +458,9 @@ type AccountReviewUpdateValue struct {
 }
 
 type BusinessCapabilityUpdateValue struct {
-	MaxDailyConversationPerPhone int `json:"max_daily_conversation_per_phone"`
-	MaxPhoneNumbersPerBusiness   int `json:"max_phone_numbers_per_business"`
+	MaxDailyConversationPerPhone     int `json:"max_daily_conversation_per_phone"`
+	MaxPhoneNumbersPerBusiness       int `json:"max_phone_numbers_per_business"`
+	MaxDailyConversationsPerBusiness int `json:"max_daily_conversations_per_business,omitempty"`
 }
 
 type SecurityValue struct {
@@ -394,6 +469,39 @@ type SecurityValue struct {
 	Requester          string `json:"requester"`
 }
 
+type FlowsEventEnum string
+
+const (
+	FlowsEventEnumFlowStatusChange     FlowsEventEnum = "FLOW_STATUS_CHANGE"
+	FlowsEventEnumClientErrorRate      FlowsEventEnum = "CLIENT_ERROR_RATE"
+	FlowsEventEnumEndpointErrorRate    FlowsEventEnum = "ENDPOINT_ERROR_RATE"
+	FlowsEventEnumEndpointLatency      FlowsEventEnum = "ENDPOINT_LATENCY"
+	FlowsEventEnumEndpointAvailability FlowsEventEnum = "ENDPOINT_AVAILABILITY"
+)
+
+type FlowsError struct {
+	ErrorType  string  `json:"error_type"`
+	ErrorRate  float64 `json:"error_rate"`
+	ErrorCount int64   `json:"error_count"`
+}
+
+// FlowsValue is the value of the flows field, whose fields depend on the event.
+type FlowsValue struct {
+	Event         FlowsEventEnum `json:"event"`
+	Message       string         `json:"message"`
+	FlowId        string         `json:"flow_id"`
+	OldStatus     FlowStatus     `json:"old_status,omitempty"`
+	NewStatus     FlowStatus     `json:"new_status,omitempty"`
+	ErrorRate     float64        `json:"error_rate,omitempty"`
+	Errors        []FlowsError   `json:"errors,omitempty"`
+	P50Latency    int64          `json:"p50_latency,omitempty"` // * in milliseconds
+	P90Latency    int64          `json:"p90_latency,omitempty"` // * in milliseconds
+	RequestsCount int64          `json:"requests_count,omitempty"`
+	Availability  float64        `json:"availability,omitempty"` // * in percent
+	Threshold     float64        `json:"threshold,omitempty"`
+	AlertState    string         `json:"alert_state,omitempty"`
+}
+
 type Change struct {
 	Value interface{}      `json:"value"`
 	Field WebhookFieldEnum `json:"field"`
@@ -415,27 +523,31 @@ type Metadata struct {
 }
 
 type Status struct {
-	Id              string       `json:"id"`
-	Conversation    Conversation `json:"conversation,omitempty"`
-	Errors          []Error      `json:"errors,omitempty"`
-	Status          string       `json:"status"`
-	Timestamp       string       `json:"timestamp"`
-	RecipientId     string       `json:"recipient_id"`
-	RecipientUserId string       `json:"recipient_user_id,omitempty"` // Business-scoped user ID (BSUID) of the recipient.
-	Pricing         Pricing      `json:"pricing,omitempty"`
+	Id                    string        `json:"id"`
+	Conversation          *Conversation `json:"conversation,omitempty"` // * absent for the statuses which are not billed, like read
+	Errors                []Error       `json:"errors,omitempty"`
+	Status                string        `json:"status"`
+	Timestamp             string        `json:"timestamp"`
+	RecipientId           string        `json:"recipient_id"`
+	RecipientUserId       string        `json:"recipient_user_id,omitempty"` // Business-scoped user ID (BSUID) of the recipient.
+	Pricing               *Pricing      `json:"pricing,omitempty"`
+	BizOpaqueCallbackData string        `json:"biz_opaque_callback_data,omitempty"` // * set when the message was sent with biz_opaque_callback_data
 }
 
 type Conversation struct {
-	Id     string `json:"id"`
-	Origin Origin `json:"origin,omitempty"`
+	Id                  string `json:"id"`
+	ExpirationTimestamp string `json:"expiration_timestamp,omitempty"` // * only present on the sent status of the message opening the conversation
+	Origin              Origin `json:"origin,omitempty"`
 }
 
 type Origin struct {
-	Type                MessageStatusCategoryEnum `json:"type"`
-	ExpirationTimestamp string                    `json:"expiration_timestamp,omitempty"`
+	Type MessageStatusCategoryEnum `json:"type"`
+	// Deprecated: the expiration is not part of the origin, see Conversation.ExpirationTimestamp.
+	ExpirationTimestamp string `json:"expiration_timestamp,omitempty"`
 }
 
 type Pricing struct {
+	Billable     bool                      `json:"billable"`
 	PricingModel string                    `json:"pricing_model"`
 	Category     MessageStatusCategoryEnum `json:"category"`
 }
@@ -446,7 +558,7 @@ type Message struct {
 	Timestamp                                       string                                      `json:"timestamp"`
 	Type                                            NotificationMessageTypeEnum                 `json:"type"`
 	Context                                         NotificationPayloadMessageContextSchemaType `json:"context"`
-	Errors                                          []Error                                     `json:",inline"`
+	Errors                                          []Error                                     `json:"errors,omitempty"`
 	NotificationPayloadTextMessageSchemaType        `json:",inline"`
 	NotificationPayloadAudioMessageSchemaType       `json:",inline"`
 	NotificationPayloadImageMessageSchemaType       `json:",inline"`
@@ -460,6 +572,22 @@ type Message struct {
 	NotificationPayloadLocationMessageSchemaType    `json:",inline"`
 	NotificationPayloadContactMessageSchemaType     `json:",inline"`
 	NotificationPayloadInteractionMessageSchemaType `json:",inline"`
+	raw                                             json.RawMessage
+}
+
+// UnmarshalJSON unmarshals the message and keeps its JSON, see Raw.
+func (message *Message) UnmarshalJSON(data []byte) error {
+	type messageFields Message
+	if err := json.Unmarshal(data, (*messageFields)(message)); err != nil {
+		return err
+	}
+	message.raw = append(json.RawMessage{}, data...)
+	return nil
+}
+
+// Raw returns the JSON the message was unmarshalled from, nil if it was not unmarshalled from JSON.
+func (message Message) Raw() json.RawMessage {
+	return message.raw
 }
 
 type Error struct {
@@ -475,7 +603,12 @@ type Error struct {
 type MessageStatusCategoryEnum string
 
 const (
-	MessageStatusCategorySent MessageStatusCategoryEnum = "sent"
+	MessageStatusCategorySent               MessageStatusCategoryEnum = "sent"
+	MessageStatusCategoryAuthentication     MessageStatusCategoryEnum = "authentication"
+	MessageStatusCategoryMarketing          MessageStatusCategoryEnum = "marketing"
+	MessageStatusCategoryUtility            MessageStatusCategoryEnum = "utility"
+	MessageStatusCategoryService            MessageStatusCategoryEnum = "service"
+	MessageStatusCategoryReferralConversion MessageStatusCategoryEnum = "referral_conversion"
 )
 
 type MessageStatusEnum string
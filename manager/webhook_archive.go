@@ -0,0 +1,126 @@
+package manager
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ArchivedWebhook is a notification as received by the webhook, kept byte for byte so that it can be replayed
+// and its signature verified again.
+type ArchivedWebhook struct {
+	ReceivedAt time.Time   `json:"received_at"`
+	Header     http.Header `json:"header,omitempty"`
+	// Signature is the X-Hub-Signature-256 header of the notification, empty when it was not signed.
+	Signature string `json:"signature,omitempty"`
+	Body      string `json:"body"`
+}
+
+// WebhookArchiver archives the raw notifications received by the webhook.
+type WebhookArchiver interface {
+	Archive(ctx context.Context, record ArchivedWebhook) error
+}
+
+// archivedHeaders are the request headers which are not archived, as they may carry credentials of a proxy.
+var archivedHeaders = []string{"Authorization", "Cookie", "Proxy-Authorization"}
+
+// newArchivedWebhook creates the record of a notification received now.
+func newArchivedWebhook(body []byte, header http.Header) ArchivedWebhook {
+	header = header.Clone()
+	for _, name := range archivedHeaders {
+		header.Del(name)
+	}
+	return ArchivedWebhook{
+		ReceivedAt: time.Now().UTC(),
+		Header:     header,
+		Signature:  header.Get(WebhookSignatureHeader),
+		Body:       string(body),
+	}
+}
+
+// FileWebhookArchive is a WebhookArchiver appending the notifications to JSONL files in a directory,
+// one file per UTC day named webhooks-YYYY-MM-DD.jsonl.
+type FileWebhookArchive struct {
+	mutex sync.Mutex
+	dir   string
+	day   string
+	file  *os.File
+}
+
+// NewFileWebhookArchive creates an archive in dir, creating the directory if it does not exist.
+func NewFileWebhookArchive(dir string) (*FileWebhookArchive, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating webhook archive directory: %w", err)
+	}
+	return &FileWebhookArchive{dir: dir}, nil
+}
+
+// Archive appends the record to the file of the day it was received.
+func (archive *FileWebhookArchive) Archive(ctx context.Context, record ArchivedWebhook) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("error encoding archived webhook: %w", err)
+	}
+
+	archive.mutex.Lock()
+	defer archive.mutex.Unlock()
+	day := record.ReceivedAt.UTC().Format(time.DateOnly)
+	if archive.file == nil || archive.day != day {
+		if archive.file != nil {
+			archive.file.Close()
+		}
+		file, err := os.OpenFile(filepath.Join(archive.dir, "webhooks-"+day+".jsonl"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			archive.file = nil
+			return fmt.Errorf("error opening webhook archive: %w", err)
+		}
+		archive.file = file
+		archive.day = day
+	}
+	if _, err := archive.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("error writing webhook archive: %w", err)
+	}
+	return nil
+}
+
+// Close closes the current file of the archive.
+func (archive *FileWebhookArchive) Close() error {
+	archive.mutex.Lock()
+	defer archive.mutex.Unlock()
+	if archive.file == nil {
+		return nil
+	}
+	err := archive.file.Close()
+	archive.file = nil
+	return err
+}
+
+// ReadWebhookArchive calls fn with every record of a JSONL archive, stopping at the first error returned by fn.
+// Lines which can not be decoded, like a line truncated by a crash, are skipped.
+func ReadWebhookArchive(reader io.Reader, fn func(record ArchivedWebhook) error) error {
+	bufferedReader := bufio.NewReader(reader)
+	for {
+		line, err := bufferedReader.ReadBytes('\n')
+		if len(line) > 0 {
+			var record ArchivedWebhook
+			if json.Unmarshal(line, &record) == nil {
+				if err := fn(record); err != nil {
+					return err
+				}
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading webhook archive: %w", err)
+		}
+	}
+}
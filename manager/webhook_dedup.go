@@ -0,0 +1,290 @@
+package manager
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDedupTTL is how long a processed delivery is remembered, Meta retries a failed delivery for up to 7 days.
+const DefaultDedupTTL = 7 * 24 * time.Hour
+
+// DedupStore remembers the deliveries already processed by the webhook, so that the messages, statuses and
+// changes redelivered by Meta are not published twice.
+type DedupStore interface {
+	// MarkSeen records key for ttl and reports whether it was already recorded and not expired.
+	// It must be atomic, so that concurrent deliveries of the same key are processed only once.
+	MarkSeen(ctx context.Context, key string, ttl time.Duration) (seen bool, err error)
+	// Forget removes key, so that a delivery which failed to be processed is processed again when redelivered.
+	Forget(ctx context.Context, key string) error
+}
+
+// dedupEntries is a set of keys with their expiry, shared by the in-memory and the file backed stores.
+type dedupEntries struct {
+	expiries  map[string]time.Time
+	lastSweep time.Time
+}
+
+func newDedupEntries() dedupEntries {
+	return dedupEntries{expiries: map[string]time.Time{}, lastSweep: time.Now()}
+}
+
+// markSeen records key and reports whether it was already recorded, it returns the expiry of the new record.
+func (entries *dedupEntries) markSeen(key string, ttl time.Duration, now time.Time) (bool, time.Time) {
+	entries.sweep(now)
+	if expiry, ok := entries.expiries[key]; ok && now.Before(expiry) {
+		return true, expiry
+	}
+	expiry := now.Add(ttl)
+	entries.expiries[key] = expiry
+	return false, expiry
+}
+
+// sweep forgets the expired keys, at most once a minute.
+func (entries *dedupEntries) sweep(now time.Time) {
+	if now.Sub(entries.lastSweep) < time.Minute {
+		return
+	}
+	entries.lastSweep = now
+	for key, expiry := range entries.expiries {
+		if !now.Before(expiry) {
+			delete(entries.expiries, key)
+		}
+	}
+}
+
+// MemoryDedupStore is a DedupStore keeping the keys in memory, which are lost when the process exits.
+type MemoryDedupStore struct {
+	mutex   sync.Mutex
+	entries dedupEntries
+}
+
+// NewMemoryDedupStore creates a new instance of MemoryDedupStore.
+func NewMemoryDedupStore() *MemoryDedupStore {
+	return &MemoryDedupStore{entries: newDedupEntries()}
+}
+
+func (store *MemoryDedupStore) MarkSeen(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	seen, _ := store.entries.markSeen(key, ttl, time.Now())
+	return seen, nil
+}
+
+func (store *MemoryDedupStore) Forget(ctx context.Context, key string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	delete(store.entries.expiries, key)
+	return nil
+}
+
+// FileDedupStore is a DedupStore keeping the keys in memory and in an append-only file, so that they
+// survive restarts. The file is compacted when the store is opened and when it holds many more records
+// than live keys, dropping the expired and forgotten keys.
+type FileDedupStore struct {
+	mutex   sync.Mutex
+	path    string
+	file    *os.File
+	entries dedupEntries
+	// records is the number of lines of the file.
+	records int
+}
+
+// NewFileDedupStore opens the file backed store at path, creating the file if it does not exist.
+func NewFileDedupStore(path string) (*FileDedupStore, error) {
+	store := &FileDedupStore{path: path, entries: newDedupEntries()}
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+	if err := store.compact(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// load reads the records of the file, each line being "<expiry in unix nanoseconds> <key>",
+// or "- <key>" for a forgotten key.
+func (store *FileDedupStore) load() error {
+	file, err := os.Open(store.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error opening dedup store: %w", err)
+	}
+	defer file.Close()
+
+	now := time.Now()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		expiryField, key, found := strings.Cut(scanner.Text(), " ")
+		if !found {
+			// a truncated last line, left by a crash while appending
+			continue
+		}
+		if expiryField == "-" {
+			delete(store.entries.expiries, key)
+			continue
+		}
+		expiry, err := strconv.ParseInt(expiryField, 10, 64)
+		if err != nil {
+			continue
+		}
+		if expiryTime := time.Unix(0, expiry); now.Before(expiryTime) {
+			store.entries.expiries[key] = expiryTime
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading dedup store: %w", err)
+	}
+	return nil
+}
+
+// compact rewrites the file with the live keys only, and opens it for appending.
+func (store *FileDedupStore) compact() error {
+	if store.file != nil {
+		store.file.Close()
+		store.file = nil
+	}
+	temporaryPath := store.path + ".tmp"
+	file, err := os.Create(temporaryPath)
+	if err != nil {
+		return fmt.Errorf("error compacting dedup store: %w", err)
+	}
+	writer := bufio.NewWriter(file)
+	for key, expiry := range store.entries.expiries {
+		fmt.Fprintf(writer, "%d %s\n", expiry.UnixNano(), key)
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("error compacting dedup store: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error compacting dedup store: %w", err)
+	}
+	if err := os.Rename(temporaryPath, store.path); err != nil {
+		return fmt.Errorf("error compacting dedup store: %w", err)
+	}
+
+	store.file, err = os.OpenFile(store.path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening dedup store: %w", err)
+	}
+	store.records = len(store.entries.expiries)
+	return nil
+}
+
+// append writes a record to the file, compacting it when it holds too many dead records.
+func (store *FileDedupStore) append(record string) error {
+	if store.file == nil {
+		return errors.New("dedup store is closed")
+	}
+	if _, err := fmt.Fprintln(store.file, record); err != nil {
+		return fmt.Errorf("error writing dedup store: %w", err)
+	}
+	store.records++
+	if store.records > 2*len(store.entries.expiries)+1024 {
+		return store.compact()
+	}
+	return nil
+}
+
+func (store *FileDedupStore) MarkSeen(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	seen, expiry := store.entries.markSeen(key, ttl, time.Now())
+	if seen {
+		return true, nil
+	}
+	if err := store.append(fmt.Sprintf("%d %s", expiry.UnixNano(), key)); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+func (store *FileDedupStore) Forget(ctx context.Context, key string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	delete(store.entries.expiries, key)
+	return store.append("- " + key)
+}
+
+// Close closes the file of the store.
+func (store *FileDedupStore) Close() error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if store.file == nil {
+		return nil
+	}
+	err := store.file.Close()
+	store.file = nil
+	return err
+}
+
+// Keys of the deliveries recorded in the DedupStore.
+func messageDedupKey(messageId string) string {
+	return "message:" + messageId
+}
+
+func statusDedupKey(status Status) string {
+	return "status:" + status.Id + ":" + status.Status
+}
+
+// changeDedupKey identifies a change by its field and the time of its entry. The hash of the value tells apart
+// the changes of the same field notified at the same second, like the status updates of two templates.
+func changeDedupKey(entry Entry, change Change) string {
+	entryTime := ""
+	if entry.Time != nil {
+		entryTime = entry.Time.String()
+	}
+	value, _ := json.Marshal(change.Value)
+	hash := sha256.Sum256(value)
+	return "change:" + entry.Id + ":" + string(change.Field) + ":" + entryTime + ":" + hex.EncodeToString(hash[:8])
+}
+
+// deliveryDeduplicator records the keys marked as seen while processing a notification,
+// so that they can be forgotten if the processing fails.
+type deliveryDeduplicator struct {
+	ctx    context.Context
+	wh     *WebhookManager
+	marked []string
+}
+
+// seen reports whether the delivery identified by key was already processed, recording it otherwise.
+// Deliveries are processed when the store fails, a duplicate being better than a lost delivery.
+func (deduplicator *deliveryDeduplicator) seen(key string) bool {
+	wh := deduplicator.wh
+	if wh.dedupStore == nil {
+		return false
+	}
+	seen, err := wh.dedupStore.MarkSeen(deduplicator.ctx, key, wh.dedupTTL)
+	if err != nil {
+		wh.logger.Warn("error checking webhook delivery for duplicates", "key", key, "error", err)
+		return false
+	}
+	if seen {
+		wh.logger.Debug("skipping duplicate webhook delivery", "key", key)
+		return true
+	}
+	deduplicator.marked = append(deduplicator.marked, key)
+	return false
+}
+
+// forget forgets the keys marked so far, when the processing of the notification failed.
+func (deduplicator *deliveryDeduplicator) forget() {
+	for _, key := range deduplicator.marked {
+		if err := deduplicator.wh.dedupStore.Forget(deduplicator.ctx, key); err != nil {
+			deduplicator.wh.logger.Warn("error forgetting webhook delivery", "key", key, "error", err)
+		}
+	}
+	deduplicator.marked = nil
+}
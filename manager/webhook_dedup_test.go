@@ -0,0 +1,180 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gTahidi/wapi.go/internal/request_client"
+	"github.com/gTahidi/wapi.go/pkg/events"
+)
+
+func TestDedupEntries(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	tests := []struct {
+		name  string
+		ttl   time.Duration
+		after time.Duration
+		want  bool
+	}{
+		{"seen again right away", time.Hour, 0, true},
+		{"seen again before expiry", time.Hour, 59 * time.Minute, true},
+		{"seen again at expiry", time.Hour, time.Hour, false},
+		{"seen again after sweep", time.Hour, 2 * time.Hour, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entries := newDedupEntries()
+			entries.lastSweep = start
+			if seen, _ := entries.markSeen("key", test.ttl, start); seen {
+				t.Fatal("first markSeen reported the key as seen")
+			}
+			if seen, _ := entries.markSeen("key", test.ttl, start.Add(test.after)); seen != test.want {
+				t.Errorf("second markSeen = %v, want %v", seen, test.want)
+			}
+		})
+	}
+}
+
+// dedupStoreFactories create the stores shared tests are run against.
+var dedupStoreFactories = map[string]func(t *testing.T) DedupStore{
+	"memory": func(t *testing.T) DedupStore {
+		return NewMemoryDedupStore()
+	},
+	"file": func(t *testing.T) DedupStore {
+		store, err := NewFileDedupStore(filepath.Join(t.TempDir(), "dedup"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { store.Close() })
+		return store
+	},
+}
+
+func TestDedupStores(t *testing.T) {
+	ctx := context.Background()
+	for name, newStore := range dedupStoreFactories {
+		t.Run(name, func(t *testing.T) {
+			store := newStore(t)
+			steps := []struct {
+				op   string
+				key  string
+				ttl  time.Duration
+				want bool
+			}{
+				{"mark", "message:1", time.Hour, false},
+				{"mark", "message:1", time.Hour, true},
+				{"mark", "message:2", time.Hour, false},
+				{"forget", "message:1", 0, false},
+				{"mark", "message:1", time.Hour, false},
+				{"mark", "message:1", time.Hour, true},
+				{"mark", "status:1:read", time.Nanosecond, false},
+				{"sleep", "", time.Millisecond, false},
+				{"mark", "status:1:read", time.Hour, false},
+				{"forget", "unknown", 0, false},
+			}
+			for index, step := range steps {
+				switch step.op {
+				case "mark":
+					seen, err := store.MarkSeen(ctx, step.key, step.ttl)
+					if err != nil {
+						t.Fatalf("step %d: %v", index, err)
+					}
+					if seen != step.want {
+						t.Errorf("step %d: MarkSeen(%q) = %v, want %v", index, step.key, seen, step.want)
+					}
+				case "forget":
+					if err := store.Forget(ctx, step.key); err != nil {
+						t.Fatalf("step %d: %v", index, err)
+					}
+				case "sleep":
+					time.Sleep(step.ttl)
+				}
+			}
+		})
+	}
+}
+
+func TestFileDedupStoreSurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "dedup")
+	store, err := NewFileDedupStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.MarkSeen(ctx, "kept", time.Hour)
+	store.MarkSeen(ctx, "forgotten", time.Hour)
+	store.MarkSeen(ctx, "expired", time.Nanosecond)
+	store.Forget(ctx, "forgotten")
+	store.Close()
+
+	// a record truncated by a crash while being appended is skipped
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("17000000")
+	file.Close()
+
+	store, err = NewFileDedupStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	for key, want := range map[string]bool{"kept": true, "forgotten": false, "expired": false} {
+		if seen, _ := store.MarkSeen(ctx, key, time.Hour); seen != want {
+			t.Errorf("MarkSeen(%q) after a restart = %v, want %v", key, seen, want)
+		}
+	}
+}
+
+func TestFileDedupStoreCompacts(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "dedup")
+	store, err := NewFileDedupStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	for i := range 2000 {
+		key := fmt.Sprintf("message:%d", i)
+		store.MarkSeen(ctx, key, time.Hour)
+		store.Forget(ctx, key)
+	}
+	store.MarkSeen(ctx, "message:kept", time.Hour)
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(content), "\n"); lines > 1100 {
+		t.Errorf("got %d records in the file, want it compacted", lines)
+	}
+	if seen, _ := store.MarkSeen(ctx, "message:kept", time.Hour); !seen {
+		t.Error("the live key was lost by the compaction")
+	}
+}
+
+func TestWebhookDeduplicatesRedeliveredMessages(t *testing.T) {
+	eventManager := NewEventManager()
+	messages, _ := eventManager.Subscribe(events.TextMessageEventType)
+	wh := NewWebhook(&WebhookManagerConfig{
+		Secret:       "secret",
+		EventManager: eventManager,
+		Requester:    *request_client.NewRequestClient("token"),
+		DedupStore:   NewMemoryDedupStore(),
+	})
+	for range 2 {
+		if response := wh.receiveNotification(context.Background(), []byte(textNotification), http.Header{}); response.status != http.StatusOK {
+			t.Fatalf("got status %d (%s), want %d", response.status, response.body, http.StatusOK)
+		}
+	}
+	if got := len(messages); got != 1 {
+		t.Errorf("got %d text message events, want 1", got)
+	}
+}
@@ -0,0 +1,23 @@
+package manager
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetRequestHandler handles GET requests to the webhook endpoint, it adapts HandleVerification to echo.
+func (wh *WebhookManager) GetRequestHandler(c echo.Context) error {
+	response := wh.verifySubscription(c.Request().URL.Query())
+	return c.String(response.status, response.body)
+}
+
+// PostRequestHandler handles POST requests to the webhook endpoint, it adapts HandleNotification to echo.
+func (wh *WebhookManager) PostRequestHandler(c echo.Context) error {
+	body, response := wh.readNotificationBody(c.Response(), c.Request())
+	if response.status != http.StatusOK {
+		return c.String(response.status, response.body)
+	}
+	response = wh.receiveNotification(c.Request().Context(), body, c.Request().Header)
+	return c.String(response.status, response.body)
+}
@@ -0,0 +1,191 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultFastAckWorkers is the number of workers processing the queued notifications when none is configured.
+	DefaultFastAckWorkers = 4
+	// DefaultFastAckMaxAttempts is how many times a queued notification is processed before being dead-lettered.
+	DefaultFastAckMaxAttempts = 5
+	// DefaultFastAckRetryBackoff is the delay before the first retry of a notification, doubled on every retry.
+	DefaultFastAckRetryBackoff = time.Second
+	// fastAckMaxRetryBackoff caps the delay between two attempts.
+	fastAckMaxRetryBackoff = time.Minute
+)
+
+// FastAckConfig enables the fast-ack mode of the webhook: the notifications are verified, written to Queue and
+// acknowledged with 200 straight away, then processed by background workers. Meta expects a response within
+// a few seconds, slow event handlers or a slow DedupStore would otherwise make it retry and eventually
+// disable the webhook.
+//
+// The handlers registered with On run asynchronously once the events are published, so the workers do not see
+// their failures: a notification is retried when its events can not be published because an event queue is
+// full, and dead-lettered when it can not be parsed or runs out of attempts, but a handler which fails is not
+// retried. The handlers have to deal with their own failures, for example by storing the event to retry it.
+type FastAckConfig struct {
+	// Queue holds the notifications until they are processed, use a FileWebhookQueue to replay them after a restart.
+	Queue WebhookQueue
+	// Workers is the number of notifications processed concurrently, DefaultFastAckWorkers by default.
+	Workers int
+	// MaxAttempts is how many times a notification is processed before being dead-lettered, DefaultFastAckMaxAttempts by default.
+	MaxAttempts int
+	// RetryBackoff is the delay before the first retry, doubled on every retry up to a minute, DefaultFastAckRetryBackoff by default.
+	RetryBackoff time.Duration
+}
+
+// fastAckWorkers is the state of the workers processing the queued notifications.
+type fastAckWorkers struct {
+	wh     *WebhookManager
+	config FastAckConfig
+	mutex  sync.Mutex
+	// cancel stops the running workers, it is nil when they are not running.
+	cancel context.CancelFunc
+	group  sync.WaitGroup
+	// active is the number of notifications being processed.
+	active atomic.Int64
+}
+
+func newFastAckWorkers(wh *WebhookManager, config FastAckConfig) *fastAckWorkers {
+	if config.Workers <= 0 {
+		config.Workers = DefaultFastAckWorkers
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultFastAckMaxAttempts
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = DefaultFastAckRetryBackoff
+	}
+	return &fastAckWorkers{wh: wh, config: config}
+}
+
+// enqueue writes the raw body of a notification to the queue, the notification is acknowledged once it is stored.
+func (workers *fastAckWorkers) enqueue(ctx context.Context, body []byte) webhookResponse {
+	delivery := WebhookDelivery{Body: body, ReceivedAt: time.Now()}
+	if err := workers.config.Queue.Enqueue(ctx, delivery); err != nil {
+		workers.wh.logger.Error("error queueing webhook notification", "error", err)
+		if errors.Is(err, ErrWebhookQueueFull) {
+			return webhookResponse{http.StatusServiceUnavailable, "Queue full"}
+		}
+		return webhookResponse{http.StatusInternalServerError, "Internal server error"}
+	}
+	return webhookResponse{http.StatusOK, "Message received"}
+}
+
+// start starts the workers if they are not running, the deliveries left in a durable queue by a previous run
+// are processed first.
+func (workers *fastAckWorkers) start() {
+	workers.mutex.Lock()
+	defer workers.mutex.Unlock()
+	if workers.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	workers.cancel = cancel
+	if pending := workers.config.Queue.Len(); pending > 0 {
+		workers.wh.logger.Info("replaying queued webhook notifications", "count", pending)
+	}
+	for range workers.config.Workers {
+		workers.group.Add(1)
+		go func() {
+			defer workers.group.Done()
+			workers.run(ctx)
+		}()
+	}
+}
+
+// stop waits for the queue to be empty, then stops the workers. When ctx is done first, the workers are stopped
+// once done with the notifications they are processing, the others are left in the queue.
+func (workers *fastAckWorkers) stop(ctx context.Context) error {
+	workers.mutex.Lock()
+	defer workers.mutex.Unlock()
+	if workers.cancel == nil {
+		return nil
+	}
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	var err error
+	for err == nil && (workers.config.Queue.Len() > 0 || workers.active.Load() > 0) {
+		select {
+		case <-ctx.Done():
+			err = fmt.Errorf("%d webhook notifications still queued: %w", workers.config.Queue.Len(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+
+	workers.cancel()
+	workers.cancel = nil
+	workers.group.Wait()
+	return err
+}
+
+// run processes the queued notifications until ctx is done.
+func (workers *fastAckWorkers) run(ctx context.Context) {
+	// the queue hands out the deliveries it holds even when ctx is done, which would take back the ones nacked
+	for ctx.Err() == nil {
+		delivery, err := workers.config.Queue.Dequeue(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				workers.wh.logger.Error("error dequeueing webhook notification", "error", err)
+			}
+			return
+		}
+		workers.active.Add(1)
+		workers.process(ctx, delivery)
+		workers.active.Add(-1)
+	}
+}
+
+// process delivers a queued notification, retrying it with backoff until it succeeds or runs out of attempts.
+// The notification is delivered once its events are published, the failures of the handlers are not seen here,
+// see FastAckConfig. The failures which can be retried are answered with a 5xx status, like the events dropped
+// because an event queue is full. A 4xx status rejects the content of the notification, like a payload which can
+// not be parsed, which would be rejected again by every attempt, so the notification is dead-lettered straight away.
+func (workers *fastAckWorkers) process(ctx context.Context, delivery WebhookDelivery) {
+	wh := workers.wh
+	backoff := workers.config.RetryBackoff
+	var response webhookResponse
+	for attempt := 1; ; attempt++ {
+		payload, parseResponse := wh.parseNotification(delivery.Body)
+		response = parseResponse
+		if response.status == http.StatusOK {
+			response = wh.deliverNotification(context.WithoutCancel(ctx), payload)
+		}
+		if response.status == http.StatusOK {
+			if err := workers.config.Queue.Ack(ctx, delivery); err != nil {
+				wh.logger.Error("error acking webhook notification", "delivery_id", delivery.Id, "error", err)
+			}
+			return
+		}
+		if response.status < http.StatusInternalServerError || attempt >= workers.config.MaxAttempts {
+			break
+		}
+
+		wh.logger.Warn("retrying webhook notification", "delivery_id", delivery.Id, "attempt", attempt, "status", response.status, "error", response.body)
+		select {
+		case <-ctx.Done():
+			// the workers are stopping, the notification is handed out again when they are started again
+			if err := workers.config.Queue.Nack(context.WithoutCancel(ctx), delivery); err != nil {
+				wh.logger.Error("error nacking webhook notification", "delivery_id", delivery.Id, "error", err)
+			}
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, fastAckMaxRetryBackoff)
+	}
+
+	reason := fmt.Sprintf("status %d: %s", response.status, response.body)
+	wh.logger.Error("dead-lettering webhook notification", "delivery_id", delivery.Id, "error", reason)
+	deadLetter := WebhookDeadLetter{Delivery: delivery, Reason: reason, At: time.Now()}
+	if err := workers.config.Queue.DeadLetter(ctx, deadLetter); err != nil {
+		wh.logger.Error("error dead-lettering webhook notification", "delivery_id", delivery.Id, "error", err)
+	}
+}
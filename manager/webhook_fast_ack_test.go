@@ -0,0 +1,103 @@
+package manager
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gTahidi/wapi.go/internal/request_client"
+	"github.com/gTahidi/wapi.go/pkg/events"
+)
+
+const textNotification = `{
+	"object": "whatsapp_business_account",
+	"entry": [{
+		"id": "102290129340398",
+		"changes": [{
+			"field": "messages",
+			"value": {
+				"messaging_product": "whatsapp",
+				"metadata": {"display_phone_number": "15550783881", "phone_number_id": "106540352242922"},
+				"contacts": [{"profile": {"name": "Sheena Nelson"}, "wa_id": "16505551234"}],
+				"messages": [{
+					"from": "16505551234",
+					"id": "wamid.HBgLMTY1MDM4Nzk0MzkVAgASGBQzQTRBNjU5OUFFRTAzODEwMTQ0RgA=",
+					"timestamp": "1749416383",
+					"type": "text",
+					"text": {"body": "Does it come in another color?"}
+				}]
+			}
+		}]
+	}]
+}`
+
+// newFastAckWebhook creates a webhook in fast-ack mode whose text message events can not be published,
+// their event queue being full, so that every attempt to process a text notification fails.
+func newFastAckWebhook(t *testing.T, queue WebhookQueue, maxAttempts int, backoff time.Duration) *WebhookManager {
+	t.Helper()
+	eventManager := NewEventManager()
+	if _, err := eventManager.Subscribe(events.TextMessageEventType); err != nil {
+		t.Fatal(err)
+	}
+	for eventManager.Publish(events.TextMessageEventType, nil) == nil {
+	}
+	wh := NewWebhook(&WebhookManagerConfig{
+		Secret:       "secret",
+		EventManager: eventManager,
+		Requester:    *request_client.NewRequestClient("token"),
+		FastAck:      &FastAckConfig{Queue: queue, Workers: 1, MaxAttempts: maxAttempts, RetryBackoff: backoff},
+	})
+	if wh == nil {
+		t.Fatal("invalid webhook config")
+	}
+	return wh
+}
+
+func TestFastAckNacksDeliveryStoppedDuringBackoff(t *testing.T) {
+	queue := NewBoundedMemoryWebhookQueue(10)
+	wh := newFastAckWebhook(t, queue, 5, time.Hour)
+
+	response := wh.receiveNotification(context.Background(), []byte(textNotification), http.Header{})
+	if response.status != http.StatusOK {
+		t.Fatalf("got status %d, want %d", response.status, http.StatusOK)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for queue.Len() > 0 || wh.fastAck.active.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the delivery was not dequeued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := wh.Shutdown(ctx); err == nil {
+		t.Fatal("Shutdown returned no error with a delivery left in the queue")
+	}
+	if got := queue.Len(); got != 1 {
+		t.Fatalf("got %d queued deliveries after the shutdown, want 1", got)
+	}
+	if got := len(queue.DeadLetters()); got != 0 {
+		t.Fatalf("got %d dead letters, want 0", got)
+	}
+}
+
+func TestFastAckDeadLettersAfterMaxAttempts(t *testing.T) {
+	queue := NewBoundedMemoryWebhookQueue(10)
+	wh := newFastAckWebhook(t, queue, 2, time.Millisecond)
+
+	wh.receiveNotification(context.Background(), []byte(textNotification), http.Header{})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := wh.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	deadLetters := queue.DeadLetters()
+	if len(deadLetters) != 1 {
+		t.Fatalf("got %d dead letters, want 1", len(deadLetters))
+	}
+	if want := "status 503: Event queue full"; deadLetters[0].Reason != want {
+		t.Fatalf("got reason %q, want %q", deadLetters[0].Reason, want)
+	}
+}
@@ -0,0 +1,68 @@
+package manager
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+// DefaultWebhookMaxBodyBytes is the size limit of the notifications when none is configured, well above the
+// size of the notifications sent by Meta.
+const DefaultWebhookMaxBodyBytes = 4 << 20
+
+// ServeHTTP makes the webhook a standard http.Handler, which answers the verification requests (GET)
+// and receives the notifications (POST) on any path it is mounted on, for example:
+//
+//	mux := http.NewServeMux()
+//	mux.Handle("/webhook", webhook)
+//
+// It works the same with routers accepting an http.Handler, like chi, with httptest or in serverless runtimes.
+func (wh *WebhookManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		wh.HandleVerification(w, r)
+	case http.MethodPost:
+		wh.HandleNotification(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeWebhookResponse(w, webhookResponse{http.StatusMethodNotAllowed, "method not allowed"})
+	}
+}
+
+// HandleVerification answers the verification request Meta sends when the webhook is subscribed.
+// Its signature matches http.HandlerFunc.
+func (wh *WebhookManager) HandleVerification(w http.ResponseWriter, r *http.Request) {
+	writeWebhookResponse(w, wh.verifySubscription(r.URL.Query()))
+}
+
+// HandleNotification receives a notification and publishes its events. Its signature matches http.HandlerFunc.
+func (wh *WebhookManager) HandleNotification(w http.ResponseWriter, r *http.Request) {
+	body, response := wh.readNotificationBody(w, r)
+	if response.status != http.StatusOK {
+		writeWebhookResponse(w, response)
+		return
+	}
+	writeWebhookResponse(w, wh.receiveNotification(r.Context(), body, r.Header))
+}
+
+// readNotificationBody reads the body of a notification request, which is rejected with 413 beyond the size
+// limit of the webhook, before its signature is verified.
+func (wh *WebhookManager) readNotificationBody(w http.ResponseWriter, r *http.Request) ([]byte, webhookResponse) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, wh.maxBodyBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			wh.logger.Warn("webhook request body too large", "limit", maxBytesErr.Limit)
+			return nil, webhookResponse{http.StatusRequestEntityTooLarge, "request body too large"}
+		}
+		wh.logger.Warn("error reading webhook request body", "error", err)
+		return nil, webhookResponse{http.StatusBadRequest, "error reading request body"}
+	}
+	return body, webhookResponse{http.StatusOK, ""}
+}
+
+func writeWebhookResponse(w http.ResponseWriter, response webhookResponse) {
+	w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	w.WriteHeader(response.status)
+	io.WriteString(w, response.body)
+}
@@ -2,38 +2,81 @@ package manager
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"log"
+	"log/slog"
 	"net/http"
-	"os"
-	"os/signal"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/gTahidi/wapi.go/internal"
 	"github.com/gTahidi/wapi.go/internal/request_client"
 	"github.com/gTahidi/wapi.go/pkg/components"
 	"github.com/gTahidi/wapi.go/pkg/events"
-	"github.com/labstack/echo/v4"
 )
 
 // WebhookManager represents a manager for handling webhooks.
 type WebhookManager struct {
 	secret       string
+	appSecret    string
+	dedupStore   DedupStore
+	dedupTTL     time.Duration
+	maxBodyBytes int64
+	fastAck      *fastAckWorkers
+	archive      WebhookArchiver
+	replay       *webhookReplay
+	tenants      *webhookRouter
+	dropped      int // dropped is the number of events which could not be queued, counted on the copy publishing a change
 	path         string
+	host         string
 	port         int
-	EventManager EventManager
+	tlsCertFile  string
+	tlsKeyFile   string
+	tlsConfig    *tls.Config
+	EventManager *EventManager
 	Requester    request_client.RequestClient
+	logger       *slog.Logger
+	serverMutex  sync.Mutex
+	server       *webhookServer
 }
 
 // WebhookManagerConfig represents the configuration options for creating a new WebhookManager.
 type WebhookManagerConfig struct {
 	Secret       string                       `validate:"required"`
-	EventManager EventManager                 `validate:"required"`
+	EventManager *EventManager                `validate:"required"`
 	Requester    request_client.RequestClient `validate:"required"`
 	Path         string
-	Port         int
+	// Host and Port are the address the built-in server listens on, all the interfaces and DefaultWebhookServerPort by default.
+	Host string
+	Port int
+	// TLSCertFile and TLSKeyFile make the built-in server serve TLS with the given certificate and key files.
+	TLSCertFile string
+	TLSKeyFile  string
+	// TLSConfig makes the built-in server serve TLS with the given configuration, the certificate of TLSCertFile is added to it.
+	TLSConfig *tls.Config
+	// AppSecret is the secret of the Meta app, used to verify the X-Hub-Signature-256 header of the notifications.
+	// Notifications which are not signed with it are rejected with 401, no verification is done when it is empty.
+	AppSecret string
+	// DedupStore enables the deduplication of the messages, statuses and changes redelivered by Meta,
+	// which are remembered for DedupTTL, DefaultDedupTTL by default.
+	DedupStore DedupStore
+	DedupTTL   time.Duration
+	// FastAck enables the fast-ack mode, where the notifications are queued and acknowledged straight away,
+	// then processed by background workers. The workers start with the webhook and stop with Shutdown.
+	FastAck *FastAckConfig
+	// Archive archives the raw notifications, once their signature is verified, so that they can be replayed
+	// with ReplayWebhookArchive, see FileWebhookArchive.
+	Archive WebhookArchiver
+	// MaxBodyBytes is the size limit of the notifications, DefaultWebhookMaxBodyBytes by default. The larger
+	// notifications are rejected with 413 without being read further.
+	MaxBodyBytes int64
+	// Logger is used to report the received notifications, the logger of the requester is used when it is nil.
+	Logger *slog.Logger
 }
 
 // NewWebhook creates a new WebhookManager with the given options.
@@ -41,66 +84,162 @@ func NewWebhook(options *WebhookManagerConfig) *WebhookManager {
 	if err := internal.GetValidator().Struct(options); err != nil {
 		return nil
 	}
-	return &WebhookManager{
+	dedupTTL := options.DedupTTL
+	if dedupTTL <= 0 {
+		dedupTTL = DefaultDedupTTL
+	}
+	maxBodyBytes := options.MaxBodyBytes
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = DefaultWebhookMaxBodyBytes
+	}
+	logger := options.Logger
+	if logger == nil {
+		logger = options.Requester.Logger()
+	}
+	wh := &WebhookManager{
 		secret:       options.Secret,
+		appSecret:    options.AppSecret,
+		dedupStore:   options.DedupStore,
+		dedupTTL:     dedupTTL,
+		maxBodyBytes: maxBodyBytes,
+		archive:      options.Archive,
 		path:         options.Path,
+		host:         options.Host,
 		port:         options.Port,
+		tlsCertFile:  options.TLSCertFile,
+		tlsKeyFile:   options.TLSKeyFile,
+		tlsConfig:    options.TLSConfig,
 		EventManager: options.EventManager,
 		Requester:    options.Requester,
+		logger:       logger,
+		tenants:      newWebhookRouter(),
+	}
+	if options.FastAck != nil && options.FastAck.Queue != nil {
+		wh.fastAck = newFastAckWorkers(wh, *options.FastAck)
+		wh.fastAck.start()
 	}
+	return wh
 }
 
-// createEchoHttpServer creates a new instance of Echo HTTP server.
-// This function is used in case the client has not provided any custom HTTP server.
-func (wh *WebhookManager) createEchoHttpServer() *echo.Echo {
-	e := echo.New()
-	return e
+// SetAccessToken swaps the access token of the requests made by the events of the webhook, like replies.
+func (wh *WebhookManager) SetAccessToken(token string) {
+	wh.SetTokenProvider(StaticTokenProvider(token))
 }
 
-// GetRequestHandler handles GET requests to the webhook endpoint.
-func (wh *WebhookManager) GetRequestHandler(c echo.Context) error {
-	hubVerificationToken := c.QueryParam("hub.verify_token")
-	hubChallenge := c.QueryParam("hub.challenge")
-	hubMode := c.QueryParam("hub.mode")
-	if hubMode == "subscribe" && hubVerificationToken == wh.secret {
-		return c.String(200, hubChallenge)
-	} else {
-		return c.String(400, "invalid token")
+// SetTokenProvider swaps the provider of the access tokens of the requests made by the events of the webhook.
+func (wh *WebhookManager) SetTokenProvider(provider TokenProvider) {
+	wh.Requester.SetTokenProvider(provider)
+}
+
+// publish publishes the event to the event manager. The events which could not be queued are logged and
+// counted, so that the notification fails and is delivered again.
+func (wh *WebhookManager) publish(eventType events.EventType, event events.BaseEvent) {
+	if wh.replay != nil && !wh.replay.accept(eventType, event) {
+		return
+	}
+	wh.logger.Debug("publishing event", request_client.LogKeyEventType, eventType)
+	if err := wh.EventManager.Publish(eventType, event); err != nil {
+		wh.logger.Warn("event dropped", request_client.LogKeyEventType, eventType, "error", err)
+		wh.dropped++
 	}
 }
 
-// PostRequestHandler handles POST requests to the webhook endpoint.
-func (wh *WebhookManager) PostRequestHandler(c echo.Context) error {
-	body, err := io.ReadAll(c.Request().Body)
-	if err != nil {
-		fmt.Println("Error reading request body:", err)
-		c.String(400, "error reading request body")
+// webhookResponse is the response to a webhook request. It is computed by the framework agnostic core
+// of the webhook and written by the net/http handlers and the router adapters.
+type webhookResponse struct {
+	status int
+	body   string
+}
+
+// verifySubscription answers the verification request Meta sends when the webhook is subscribed.
+func (wh *WebhookManager) verifySubscription(query url.Values) webhookResponse {
+	if query.Get("hub.mode") == "subscribe" && query.Get("hub.verify_token") == wh.secret {
+		return webhookResponse{http.StatusOK, query.Get("hub.challenge")}
+	}
+	return webhookResponse{http.StatusBadRequest, "invalid token"}
+}
+
+// receiveNotification verifies and parses the raw body of a notification, and publishes its events,
+// or queues it for the workers in fast-ack mode.
+func (wh *WebhookManager) receiveNotification(ctx context.Context, body []byte, header http.Header) webhookResponse {
+	if wh.appSecret != "" {
+		if err := VerifyWebhookSignature(wh.appSecret, body, header.Get(WebhookSignatureHeader)); err != nil {
+			wh.logger.Warn("rejected webhook request", "error", err)
+			return webhookResponse{http.StatusUnauthorized, "invalid signature"}
+		}
 	}
 
+	if wh.archive != nil {
+		if err := wh.archive.Archive(ctx, newArchivedWebhook(body, header)); err != nil {
+			wh.logger.Warn("error archiving webhook notification", "error", err)
+		}
+	}
+
+	payload, response := wh.parseNotification(body)
+	if response.status != http.StatusOK {
+		return response
+	}
+
+	if wh.fastAck != nil {
+		return wh.fastAck.enqueue(ctx, body)
+	}
+	return wh.deliverNotification(ctx, payload)
+}
+
+// parseNotification parses and validates the raw body of a notification.
+func (wh *WebhookManager) parseNotification(body []byte) (WhatsappApiNotificationPayloadSchemaType, webhookResponse) {
 	var payload WhatsappApiNotificationPayloadSchemaType
 	if err := json.Unmarshal(body, &payload); err != nil {
-		fmt.Println("Error unmarshaling JSON:", err)
-		c.String(400, "Invalid JSON data")
+		wh.logger.Warn("error unmarshalling webhook payload", "error", err)
+		return payload, webhookResponse{http.StatusBadRequest, "Invalid JSON data"}
 	}
 
 	if err := internal.GetValidator().Struct(payload); err != nil {
-		fmt.Println("Error validating JSON:", err)
-		c.String(400, "Invalid JSON data")
+		wh.logger.Warn("invalid webhook payload", "error", err)
+		return payload, webhookResponse{http.StatusBadRequest, "Invalid JSON data"}
 	}
+	return payload, webhookResponse{http.StatusOK, "Message received"}
+}
 
+// deliverNotification publishes the events of a parsed notification, deduplicating its deliveries.
+func (wh *WebhookManager) deliverNotification(ctx context.Context, payload WhatsappApiNotificationPayloadSchemaType) webhookResponse {
+	deduplicator := &deliveryDeduplicator{ctx: ctx, wh: wh}
+	response := wh.processNotification(deduplicator, payload)
+	if response.status != http.StatusOK {
+		// the deliveries will be processed again when the notification is redelivered
+		deduplicator.forget()
+	}
+	return response
+}
+
+// processNotification publishes the events of a notification, skipping the deliveries already processed.
+func (wh *WebhookManager) processNotification(deduplicator *deliveryDeduplicator, payload WhatsappApiNotificationPayloadSchemaType) webhookResponse {
 	for _, entry := range payload.Entry {
 		for _, change := range entry.Changes {
+			// messages and statuses are deduplicated one by one, as a single delivery can batch several of them
+			if change.Field != WebhookFieldEnumMessages && deduplicator.seen(changeDedupKey(entry, change)) {
+				continue
+			}
+			// the events of the changes of a tenant are published to its own event manager
+			target := wh.changeWebhook(entry, change)
 			switch change.Field {
 			case WebhookFieldEnumMessages:
 				var messageValue MessagesValue
 				valueBytes, err := json.Marshal(change.Value)
 				if err != nil {
-					return c.String(http.StatusInternalServerError, "Error marshaling messages value")
+					return webhookResponse{http.StatusInternalServerError, "Error marshaling messages value"}
 				}
 				if err := json.Unmarshal(valueBytes, &messageValue); err != nil {
-					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid MessagesValue JSON: %v", err))
+					return webhookResponse{http.StatusBadRequest, fmt.Sprintf("Invalid MessagesValue JSON: %v", err)}
 				}
 
+				messageValue.Messages = slices.DeleteFunc(messageValue.Messages, func(message Message) bool {
+					return deduplicator.seen(messageDedupKey(message.Id))
+				})
+				messageValue.Statuses = slices.DeleteFunc(messageValue.Statuses, func(status Status) bool {
+					return deduplicator.seen(statusDedupKey(status))
+				})
+
 				senderName := ""
 				senderUserId := ""
 				if len(messageValue.Contacts) > 0 {
@@ -108,7 +247,7 @@ func (wh *WebhookManager) PostRequestHandler(c echo.Context) error {
 					senderUserId = messageValue.Contacts[0].UserId
 				}
 
-				err = wh.handleMessagesSubscriptionEvents(HandleMessageSubscriptionEventPayload{
+				err = target.handleMessagesSubscriptionEvents(HandleMessageSubscriptionEventPayload{
 					Messages: messageValue.Messages,
 					Statuses: messageValue.Statuses,
 					PhoneNumber: events.BusinessPhoneNumber{
@@ -119,224 +258,34 @@ func (wh *WebhookManager) PostRequestHandler(c echo.Context) error {
 					SenderName:        senderName,
 					SenderUserId:      senderUserId,
 					UserActions:       messageValue.UserActions,
+					Errors:            messageValue.Errors,
+					RawValue:          valueBytes,
 				})
 
 				if err != nil {
-					fmt.Println("Error handling messages subscription events:", err)
-					c.String(500, "Internal server error")
-					return err
-				}
-			case WebhookFieldEnumAccountReview:
-				var accountReviewValue AccountReviewUpdateValue
-				valueBytes, err := json.Marshal(change.Value)
-				if err != nil {
-					return c.String(http.StatusInternalServerError, "Error marshaling messages value")
-				}
-				if err := json.Unmarshal(valueBytes, &accountReviewValue); err != nil {
-					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid accountReviewValue JSON: %v", err))
-				}
-				err = wh.handleAccountReviewSubscriptionEvents(events.BaseBusinessAccountEvent{
-					BusinessAccountId: entry.Id,
-					Timestamp:         *entry.Time,
-				}, accountReviewValue)
-				if err != nil {
-					fmt.Println("Error handling messages subscription events:", err)
-					c.String(500, "Internal server error")
-					return err
-				}
-			case WebhookFieldEnumAccountAlerts:
-				var accountAlertValue AccountAlertsValue
-				valueBytes, err := json.Marshal(change.Value)
-				if err != nil {
-					return c.String(http.StatusInternalServerError, "Error marshaling messages value")
-				}
-				if err := json.Unmarshal(valueBytes, &accountAlertValue); err != nil {
-					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid accountAlertValue JSON: %v", err))
-				}
-				err = wh.handleAccountAlertsSubscriptionEvents(events.BaseBusinessAccountEvent{
-					BusinessAccountId: entry.Id,
-					Timestamp:         *entry.Time,
-				}, accountAlertValue)
-				if err != nil {
-					fmt.Println("Error handling messages subscription events:", err)
-					c.String(500, "Internal server error")
-					return err
-				}
-			case WebhookFieldEnumAccountUpdate:
-				var accountUpdate AccountUpdateValue
-				valueBytes, err := json.Marshal(change.Value)
-				if err != nil {
-					return c.String(http.StatusInternalServerError, "Error marshaling messages value")
-				}
-				if err := json.Unmarshal(valueBytes, &accountUpdate); err != nil {
-					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid accountUpdate JSON: %v", err))
-				}
-				wh.handleAccountUpdateSubscriptionEvents(events.BaseBusinessAccountEvent{
-					BusinessAccountId: entry.Id,
-					Timestamp:         *entry.Time,
-				}, accountUpdate)
-				if err != nil {
-					fmt.Println("Error handling messages subscription events:", err)
-					c.String(500, "Internal server error")
-					return err
-				}
-			case WebhookFieldEnumTemplateCategoryUpdate:
-				var templateCategoryUpdate TemplateCategoryUpdateValue
-				valueBytes, err := json.Marshal(change.Value)
-				if err != nil {
-					return c.String(http.StatusInternalServerError, "Error marshaling messages value")
-				}
-				if err := json.Unmarshal(valueBytes, &templateCategoryUpdate); err != nil {
-					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid templateCategoryUpdate JSON: %v", err))
-				}
-				wh.handleTemplateCategoryUpdateSubscriptionEvents(events.BaseBusinessAccountEvent{
-					BusinessAccountId: entry.Id,
-					Timestamp:         *entry.Time,
-				}, templateCategoryUpdate)
-				if err != nil {
-					fmt.Println("Error handling messages subscription events:", err)
-					c.String(500, "Internal server error")
-					return err
-				}
-			case WebhookFieldEnumMessageTemplateQuality:
-				var qualityUpdate TemplateQualityUpdateValue
-				valueBytes, err := json.Marshal(change.Value)
-				if err != nil {
-					return c.String(http.StatusInternalServerError, "Error marshaling messages value")
-				}
-				if err := json.Unmarshal(valueBytes, &qualityUpdate); err != nil {
-					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid qualityUpdate JSON: %v", err))
-				}
-				wh.handleMessageTemplateQualitySubscriptionEvents(events.BaseBusinessAccountEvent{
-					BusinessAccountId: entry.Id,
-					Timestamp:         *entry.Time,
-				}, qualityUpdate)
-				if err != nil {
-					fmt.Println("Error handling messages subscription events:", err)
-					c.String(500, "Internal server error")
-					return err
-				}
-			case WebhookFieldEnumMessageTemplateStatus:
-				var statusUpdate TemplateStatusUpdateValue
-				valueBytes, err := json.Marshal(change.Value)
-				if err != nil {
-					return c.String(http.StatusInternalServerError, "Error marshaling messages value")
-				}
-				if err := json.Unmarshal(valueBytes, &statusUpdate); err != nil {
-					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid statusUpdate JSON: %v", err))
-				}
-				wh.handleMessageTemplateStatusSubscriptionEvents(events.BaseBusinessAccountEvent{
-					BusinessAccountId: entry.Id,
-					Timestamp:         *entry.Time,
-				}, statusUpdate)
-				if err != nil {
-					fmt.Println("Error handling messages subscription events:", err)
-					c.String(500, "Internal server error")
-					return err
-				}
-			case WebhookFieldEnumPhoneNumberName:
-				var nameUpdate PhoneNumberNameUpdateValue
-				valueBytes, err := json.Marshal(change.Value)
-				if err != nil {
-					return c.String(http.StatusInternalServerError, "Error marshaling messages value")
-				}
-				if err := json.Unmarshal(valueBytes, &nameUpdate); err != nil {
-					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid nameUpdate JSON: %v", err))
-				}
-				wh.handlePhoneNumberNameSubscriptionEvents(events.BaseBusinessAccountEvent{
-					BusinessAccountId: entry.Id,
-					Timestamp:         *entry.Time,
-				}, nameUpdate)
-				if err != nil {
-					fmt.Println("Error handling messages subscription events:", err)
-					c.String(500, "Internal server error")
-					return err
-				}
-			case WebhookFieldEnumPhoneNumberQuality:
-				var qualityUpdate PhoneNumberQualityUpdateValue
-				valueBytes, err := json.Marshal(change.Value)
-				if err != nil {
-					return c.String(http.StatusInternalServerError, "Error marshaling messages value")
-				}
-				if err := json.Unmarshal(valueBytes, &qualityUpdate); err != nil {
-					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid qualityUpdate JSON: %v", err))
-				}
-				wh.handlePhoneNumberQualitySubscriptionEvents(events.BaseBusinessAccountEvent{
-					BusinessAccountId: entry.Id,
-					Timestamp:         *entry.Time,
-				}, qualityUpdate)
-				if err != nil {
-					fmt.Println("Error handling messages subscription events:", err)
-					c.String(500, "Internal server error")
-					return err
-				}
-			case WebhookFieldEnumBusinessCapability:
-				var capabilityUpdate BusinessCapabilityUpdateValue
-				valueBytes, err := json.Marshal(change.Value)
-				if err != nil {
-					return c.String(http.StatusInternalServerError, "Error marshaling messages value")
-				}
-				if err := json.Unmarshal(valueBytes, &capabilityUpdate); err != nil {
-					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid capabilityUpdate JSON: %v", err))
-				}
-				wh.handleBusinessCapabilitySubscriptionEvents(events.BaseBusinessAccountEvent{
-					BusinessAccountId: entry.Id,
-					Timestamp:         *entry.Time,
-				}, capabilityUpdate)
-				if err != nil {
-					fmt.Println("Error handling messages subscription events:", err)
-					c.String(500, "Internal server error")
-					return err
+					wh.logger.Error("error handling webhook change", "field", change.Field, "error", err)
+					return webhookResponse{http.StatusInternalServerError, "Internal server error"}
 				}
-			case WebhookFieldEnumSecurity:
-				var securityChange SecurityValue
-				valueBytes, err := json.Marshal(change.Value)
-				if err != nil {
-					return c.String(http.StatusInternalServerError, "Error marshaling messages value")
-				}
-				if err := json.Unmarshal(valueBytes, &securityChange); err != nil {
-					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid securityChange JSON: %v", err))
-				}
-				wh.handleSecuritySubscriptionEvents(securityChange)
-				if err != nil {
-					fmt.Println("Error handling messages subscription events:", err)
-					c.String(500, "Internal server error")
-					return err
+			default:
+				if err := target.processBusinessAccountChange(entry, change); err != nil {
+					var valueErr *changeValueError
+					if errors.As(err, &valueErr) {
+						wh.logger.Warn("invalid webhook change value", "field", change.Field, "error", err)
+						return webhookResponse{http.StatusBadRequest, err.Error()}
+					}
+					wh.logger.Error("error handling webhook change", "field", change.Field, "error", err)
+					return webhookResponse{http.StatusInternalServerError, "Internal server error"}
 				}
 			}
+			if target.dropped > 0 {
+				// the notification is delivered again as a whole, the events already queued are published again
+				wh.logger.Error("events of webhook change dropped", "field", change.Field, "dropped", target.dropped)
+				return webhookResponse{http.StatusServiceUnavailable, "Event queue full"}
+			}
 		}
 	}
 
-	c.String(200, "Message received")
-	return nil
-}
-
-// ListenToEvents starts listening to events and handles incoming requests.
-func (wh *WebhookManager) ListenToEvents() {
-	fmt.Println("Listening to events")
-	server := wh.createEchoHttpServer()
-	server.GET(wh.path, wh.GetRequestHandler)
-	server.POST(wh.path, wh.PostRequestHandler)
-
-	// Start server in a goroutine
-	go func() {
-		if err := server.Start("127.0.0.1:8080"); err != nil {
-			return
-		}
-	}()
-
-	wh.EventManager.Publish(events.ReadyEventType, events.NewReadyEvent())
-	// Wait for an interrupt signal (e.g., Ctrl+C)
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt) // Capture SIGINT (Ctrl+C)
-	<-quit                            // Wait for the signal
-
-	// Gracefully shut down the server (optional)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	if err := server.Shutdown(ctx); err != nil {
-		log.Fatal(err) // Handle shutdown errors gracefully
-	}
+	return webhookResponse{http.StatusOK, "Message received"}
 }
 
 type HandleMessageSubscriptionEventPayload struct {
@@ -347,70 +296,52 @@ type HandleMessageSubscriptionEventPayload struct {
 	SenderName        string                     `json:"sender_name"`
 	SenderUserId      string                     `json:"sender_user_id"` // * business-scoped user ID (BSUID) of the sender
 	UserActions       []UserAction               `json:"user_actions"`
+	Errors            []Error                    `json:"errors"`    // * errors reported by Meta for the whole notification
+	RawValue          json.RawMessage            `json:"raw_value"` // * JSON of the notification value
 }
 
 func (wh *WebhookManager) handleMessagesSubscriptionEvents(payload HandleMessageSubscriptionEventPayload) error {
-	// consider the field here too, because we will be supporting more events
-	if len(payload.Statuses) > 0 {
-		for _, status := range payload.Statuses {
-			switch status.Status {
-			case string(MessageStatusDelivered):
-				{
-					wh.EventManager.Publish(events.MessageDeliveredEventType, events.NewMessageDeliveredEvent(events.BaseSystemEvent{
-						Timestamp: status.Timestamp,
-					}, status.Id, status.RecipientId, status.RecipientUserId))
-				}
-
-			case string(MessageStatusRead):
-				{
-					wh.EventManager.Publish(events.MessageReadEventType, events.NewMessageReadEvent(events.BaseSystemEvent{
-						Timestamp: status.Timestamp,
-					}, status.Id, status.RecipientId, status.RecipientUserId))
-				}
-			case string(MessageStatusSent):
-				{
-					wh.EventManager.Publish(events.MessageSentEventType, events.NewMessageSentEvent(events.BaseSystemEvent{
-						Timestamp: status.Timestamp,
-					}, status.Id, status.RecipientId, status.RecipientUserId))
-				}
-			case string(MessageStatusFailed):
-				{
-					failedReason := ""
-					errorCode := 0
-					errorMessage := ""
-					if len(status.Errors) > 0 {
-						for _, err := range status.Errors {
-							failedReason = err.Title
-							errorCode = err.Code
-							errorMessage = err.Message
-							break
-						}
-					}
-
-					wh.EventManager.Publish(events.MessageFailedEventType, events.NewMessageFailedEvent(events.BaseSystemEvent{
-						Timestamp: status.Timestamp,
-					}, status.Id, status.RecipientId, status.RecipientUserId, failedReason, errorCode, errorMessage))
-				}
-			case string(MessageStatusUnDelivered):
-				{
-					undeliveredReason := ""
-					errorCode := 0
-					errorMessage := ""
-					if len(status.Errors) > 0 {
-						for _, err := range status.Errors {
-							undeliveredReason = err.Title
-							errorCode = err.Code
-							errorMessage = err.Message
-							break
-						}
-					}
-
-					wh.EventManager.Publish(events.MessageUndeliveredEventType, events.NewMessageUndeliveredEvent(events.BaseSystemEvent{
-						Timestamp: status.Timestamp,
-					}, status.Id, status.RecipientId, status.RecipientUserId, undeliveredReason, errorCode, errorMessage))
-				}
-			}
+	if len(payload.Errors) > 0 {
+		wh.logger.Warn("webhook notification reports errors", request_client.LogKeyPhoneNumberId, payload.PhoneNumber.Id, "errors", len(payload.Errors))
+		wh.publish(events.ErrorEventType, events.NewErrorEvent(events.BaseSystemEvent{
+			Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
+		}, events.WebhookIssue{
+			BusinessAccountId: payload.BusinessAccountId,
+			PhoneNumber:       payload.PhoneNumber,
+			Errors:            webhookErrors(payload.Errors),
+			Raw:               payload.RawValue,
+		}))
+	}
 
+	// consider the field here too, because we will be supporting more events
+	for _, status := range payload.Statuses {
+		baseSystemEvent := events.BaseSystemEvent{
+			Timestamp: status.Timestamp,
+		}
+		details := messageStatusDetails(status)
+		switch status.Status {
+		case string(MessageStatusDelivered):
+			event := events.NewMessageDeliveredEvent(baseSystemEvent, status.Id, status.RecipientId, status.RecipientUserId)
+			event.MessageStatusDetails = details
+			wh.publish(events.MessageDeliveredEventType, event)
+		case string(MessageStatusRead):
+			event := events.NewMessageReadEvent(baseSystemEvent, status.Id, status.RecipientId, status.RecipientUserId)
+			event.MessageStatusDetails = details
+			wh.publish(events.MessageReadEventType, event)
+		case string(MessageStatusSent):
+			event := events.NewMessageSentEvent(baseSystemEvent, status.Id, status.RecipientId, status.RecipientUserId)
+			event.MessageStatusDetails = details
+			wh.publish(events.MessageSentEventType, event)
+		case string(MessageStatusFailed):
+			failedReason, errorCode, errorMessage := statusError(status)
+			event := events.NewMessageFailedEvent(baseSystemEvent, status.Id, status.RecipientId, status.RecipientUserId, failedReason, errorCode, errorMessage)
+			event.MessageStatusDetails = details
+			wh.publish(events.MessageFailedEventType, event)
+		case string(MessageStatusUnDelivered):
+			undeliveredReason, errorCode, errorMessage := statusError(status)
+			event := events.NewMessageUndeliveredEvent(baseSystemEvent, status.Id, status.RecipientId, status.RecipientUserId, undeliveredReason, errorCode, errorMessage)
+			event.MessageStatusDetails = details
+			wh.publish(events.MessageUndeliveredEventType, event)
 		}
 	}
 
@@ -432,13 +363,46 @@ func (wh *WebhookManager) handleMessagesSubscriptionEvents(payload HandleMessage
 			Context: events.MessageContext{
 				RepliedToMessageId: repliedTo,
 			},
+			Referral:  messageReferral(message),
 			Requester: wh.Requester,
 		})
 
+		if baseMessageEvent.Referral != nil {
+			// published along with the event of the message, for the attribution of the messages sent from ads
+			wh.publish(events.AdInteractionEventType, events.NewAdInteractionEvent(
+				baseMessageEvent,
+				*baseMessageEvent.Referral,
+				messageText(message),
+			))
+		}
+
+		if referredProduct := message.Context.ReferredProduct; referredProduct.ProductRetailerId != "" {
+			// published along with the event of the message, for the messages sent about a product of a catalog
+			wh.publish(events.ProductInquiryEventType, events.NewProductInquiryEvent(
+				baseMessageEvent,
+				referredProduct.ProductRetailerId,
+				referredProduct.CatalogId,
+				messageText(message),
+			))
+		}
+
+		isSupported := message.Type != NotificationMessageTypeUnknown && message.Type != NotificationMessageTypeUnsupported
+		if len(message.Errors) > 0 && isSupported {
+			wh.publish(events.WarnEventType, events.NewWarnEvent(events.BaseSystemEvent{
+				Timestamp: message.Timestamp,
+			}, events.WebhookIssue{
+				BusinessAccountId: payload.BusinessAccountId,
+				PhoneNumber:       payload.PhoneNumber,
+				MessageId:         message.Id,
+				Errors:            webhookErrors(message.Errors),
+				Raw:               message.Raw(),
+			}))
+		}
+
 		switch message.Type {
 		case NotificationMessageTypeText:
 			{
-				wh.EventManager.Publish(events.TextMessageEventType, events.NewTextMessageEvent(
+				wh.publish(events.TextMessageEventType, events.NewTextMessageEvent(
 					baseMessageEvent,
 					message.Text.Body),
 				)
@@ -451,12 +415,11 @@ func (wh *WebhookManager) handleMessagesSubscriptionEvents(payload HandleMessage
 				})
 
 				if err != nil {
-					// ! TODO: emit error event here
-					fmt.Println("Error creating image message:", err)
-					return err
+					wh.publishMessageError(payload, message, fmt.Errorf("error creating image message: %w", err))
+					continue
 				}
 
-				wh.EventManager.Publish(events.ImageMessageEventType, events.NewImageMessageEvent(
+				wh.publish(events.ImageMessageEventType, events.NewImageMessageEvent(
 					baseMessageEvent,
 					*imageMessageComponent,
 					message.Image.MIMEType, message.Image.SHA256, message.Image.Id),
@@ -470,12 +433,11 @@ func (wh *WebhookManager) handleMessagesSubscriptionEvents(payload HandleMessage
 				})
 
 				if err != nil {
-					// ! TODO: emit error event here
-					fmt.Println("Error creating audio message:", err)
-					return err
+					wh.publishMessageError(payload, message, fmt.Errorf("error creating audio message: %w", err))
+					continue
 				}
 
-				wh.EventManager.Publish(events.AudioMessageEventType, events.NewAudioMessageEvent(
+				wh.publish(events.AudioMessageEventType, events.NewAudioMessageEvent(
 					baseMessageEvent,
 					*audioMessageComponent,
 					message.Audio.MIMEType, message.Audio.SHA256, message.Audio.Id),
@@ -491,12 +453,11 @@ func (wh *WebhookManager) handleMessagesSubscriptionEvents(payload HandleMessage
 				})
 
 				if err != nil {
-					// ! TODO: emit error event here
-					fmt.Println("Error creating Video message:", err)
-					return err
+					wh.publishMessageError(payload, message, fmt.Errorf("error creating video message: %w", err))
+					continue
 				}
 
-				wh.EventManager.Publish(events.VideoMessageEventType, events.NewVideoMessageEvent(
+				wh.publish(events.VideoMessageEventType, events.NewVideoMessageEvent(
 					baseMessageEvent,
 					*videoMessageComponent,
 					message.Video.MIMEType, message.Video.SHA256, message.Video.Id),
@@ -511,12 +472,11 @@ func (wh *WebhookManager) handleMessagesSubscriptionEvents(payload HandleMessage
 				})
 
 				if err != nil {
-					// ! TODO: emit error event here
-					fmt.Println("Error creating document message:", err)
-					return err
+					wh.publishMessageError(payload, message, fmt.Errorf("error creating document message: %w", err))
+					continue
 				}
 
-				wh.EventManager.Publish(events.DocumentMessageEventType, events.NewVideoMessageEvent(
+				wh.publish(events.DocumentMessageEventType, events.NewVideoMessageEvent(
 					baseMessageEvent,
 					*documentMessageComponent,
 					message.Document.MIMEType, message.Document.SHA256, message.Document.Id),
@@ -527,21 +487,23 @@ func (wh *WebhookManager) handleMessagesSubscriptionEvents(payload HandleMessage
 				locationMessageComponent, err := components.NewLocationMessage(message.Location.Latitude, message.Location.Longitude)
 
 				if err != nil {
-					// ! TODO: emit error event here
-					fmt.Println("Error creating location message:", err)
-					return err
+					wh.publishMessageError(payload, message, fmt.Errorf("error creating location message: %w", err))
+					continue
 				}
 
-				wh.EventManager.Publish(events.LocationMessageEventType, events.NewLocationMessageEvent(
+				wh.publish(events.LocationMessageEventType, events.NewLocationMessageEvent(
 					baseMessageEvent,
 					*locationMessageComponent),
 				)
 			}
 		case NotificationMessageTypeContacts:
 			{
-				contactMessageComponent, _ := components.NewContactMessage([]components.Contact{})
-				// ! TODO: add the contact here to the contact message component
-				wh.EventManager.Publish(events.ContactMessageEventType, events.NewContactsMessageEvent(
+				contactMessageComponent, err := components.NewContactMessage(sharedContacts(message.Contacts))
+				if err != nil {
+					wh.publishMessageError(payload, message, fmt.Errorf("error creating contact message: %w", err))
+					continue
+				}
+				wh.publish(events.ContactMessageEventType, events.NewContactsMessageEvent(
 					baseMessageEvent,
 					*contactMessageComponent,
 				))
@@ -554,12 +516,11 @@ func (wh *WebhookManager) handleMessagesSubscriptionEvents(payload HandleMessage
 				})
 
 				if err != nil {
-					// ! TODO: emit error event here
-					fmt.Println("Error creating Sticker message:", err)
-					return err
+					wh.publishMessageError(payload, message, fmt.Errorf("error creating sticker message: %w", err))
+					continue
 				}
 
-				wh.EventManager.Publish(events.StickerMessageEventType, events.NewStickerMessageEvent(
+				wh.publish(events.StickerMessageEventType, events.NewStickerMessageEvent(
 					baseMessageEvent,
 					*stickerMessageComponent,
 					message.Sticker.MIMEType, message.Sticker.SHA256, message.Sticker.Id),
@@ -568,7 +529,7 @@ func (wh *WebhookManager) handleMessagesSubscriptionEvents(payload HandleMessage
 			}
 		case NotificationMessageTypeButton:
 			{
-				wh.EventManager.Publish(events.QuickReplyMessageEventType, events.NewQuickReplyButtonInteractionEvent(
+				wh.publish(events.QuickReplyMessageEventType, events.NewQuickReplyButtonInteractionEvent(
 					baseMessageEvent,
 					message.Button.Text,
 					message.Button.Payload,
@@ -576,19 +537,34 @@ func (wh *WebhookManager) handleMessagesSubscriptionEvents(payload HandleMessage
 			}
 		case NotificationMessageTypeInteractive:
 			{
-				if message.Interactive.Type == "list_reply" {
-					wh.EventManager.Publish(events.ListInteractionMessageEventType, events.NewListInteractionEvent(
+				switch message.Interactive.Type {
+				case NotificationTypeListReply:
+					wh.publish(events.ListInteractionMessageEventType, events.NewListInteractionEvent(
 						baseMessageEvent,
 						message.Interactive.ListReply.Title,
 						message.Interactive.ListReply.Id,
 						message.Interactive.ListReply.Description,
 					))
-				} else {
-					wh.EventManager.Publish(events.ReplyButtonInteractionEventType, events.NewReplyButtonInteractionEvent(
+				case NotificationTypeButtonReply:
+					wh.publish(events.ReplyButtonInteractionEventType, events.NewReplyButtonInteractionEvent(
 						baseMessageEvent,
 						message.Interactive.ButtonReply.Title,
 						message.Interactive.ButtonReply.Id,
 					))
+				case NotificationTypeNfmReply:
+					flowCompletionEvent, err := events.NewFlowCompletionEvent(
+						baseMessageEvent,
+						message.Interactive.NfmReply.Name,
+						message.Interactive.NfmReply.Body,
+						message.Interactive.NfmReply.ResponseJson,
+					)
+					if err != nil {
+						wh.publishMessageError(payload, message, fmt.Errorf("error creating flow completion event: %w", err))
+						continue
+					}
+					wh.publish(events.FlowCompletionEventType, flowCompletionEvent)
+				default:
+					wh.publishUnknownMessage(payload, message, baseMessageEvent)
 				}
 
 			}
@@ -600,12 +576,11 @@ func (wh *WebhookManager) handleMessagesSubscriptionEvents(payload HandleMessage
 				})
 
 				if err != nil {
-					// ! TODO: emit error event here
-					fmt.Println("Error creating location message:", err)
-					return err
+					wh.publishMessageError(payload, message, fmt.Errorf("error creating reaction message: %w", err))
+					continue
 				}
 
-				wh.EventManager.Publish(events.ReactionMessageEventType, events.NewReactionMessageEvent(
+				wh.publish(events.ReactionMessageEventType, events.NewReactionMessageEvent(
 					baseMessageEvent,
 					*reactionMessageComponent,
 				))
@@ -623,7 +598,7 @@ func (wh *WebhookManager) handleMessagesSubscriptionEvents(payload HandleMessage
 					}
 				}
 
-				wh.EventManager.Publish(events.OrderReceivedEventType, events.NewOrderEvent(
+				wh.publish(events.OrderReceivedEventType, events.NewOrderEvent(
 					baseMessageEvent,
 					components.Order{
 						CatalogID:    message.Order.CatalogId,
@@ -635,7 +610,7 @@ func (wh *WebhookManager) handleMessagesSubscriptionEvents(payload HandleMessage
 		case NotificationMessageTypeSystem:
 			{
 				if message.System.Type == SystemNotificationTypeCustomerIdentityChanged {
-					wh.EventManager.Publish(events.CustomerIdentityChangedEventType, events.CustomerIdentityChangedEvent{
+					wh.publish(events.CustomerIdentityChangedEventType, events.CustomerIdentityChangedEvent{
 						BaseSystemEvent: events.BaseSystemEvent{
 							Timestamp: message.Timestamp,
 						},
@@ -644,7 +619,7 @@ func (wh *WebhookManager) handleMessagesSubscriptionEvents(payload HandleMessage
 						Hash:              message.Identity.Hash,
 					})
 				} else {
-					wh.EventManager.Publish(events.CustomerNumberChangedEventType, events.CustomerNumberChangedEvent{
+					wh.publish(events.CustomerNumberChangedEventType, events.CustomerNumberChangedEvent{
 						BaseSystemEvent: events.BaseSystemEvent{
 							Timestamp: message.Timestamp,
 						},
@@ -654,10 +629,9 @@ func (wh *WebhookManager) handleMessagesSubscriptionEvents(payload HandleMessage
 					})
 				}
 			}
-		case NotificationMessageTypeUnknown:
-			{
-				// ! TODO: handle error in the event and then emit it.
-			}
+		default:
+			// unknown, unsupported and any type this version does not handle yet
+			wh.publishUnknownMessage(payload, message, baseMessageEvent)
 		}
 	}
 
@@ -665,7 +639,7 @@ func (wh *WebhookManager) handleMessagesSubscriptionEvents(payload HandleMessage
 	for _, action := range payload.UserActions {
 		switch action.ActionType {
 		case UserActionTypeMarketingMessagesLinkClick:
-			wh.EventManager.Publish(events.MarketingMessagesLinkClickEventType,
+			wh.publish(events.MarketingMessagesLinkClickEventType,
 				events.NewMarketingMessagesLinkClickEvent(
 					events.BaseBusinessAccountEvent{
 						BusinessAccountId: payload.BusinessAccountId,
@@ -685,8 +659,289 @@ func (wh *WebhookManager) handleMessagesSubscriptionEvents(payload HandleMessage
 	return nil
 }
 
+// messageStatusDetails returns the billing details and the callback data of a status.
+func messageStatusDetails(status Status) events.MessageStatusDetails {
+	details := events.MessageStatusDetails{
+		BizOpaqueCallbackData: status.BizOpaqueCallbackData,
+	}
+	if status.Conversation != nil {
+		expirationTimestamp := status.Conversation.ExpirationTimestamp
+		if expirationTimestamp == "" {
+			expirationTimestamp = status.Conversation.Origin.ExpirationTimestamp
+		}
+		details.Conversation = &events.MessageStatusConversation{
+			Id:                  status.Conversation.Id,
+			OriginType:          string(status.Conversation.Origin.Type),
+			ExpirationTimestamp: expirationTimestamp,
+		}
+	}
+	if status.Pricing != nil {
+		details.Pricing = &events.MessageStatusPricing{
+			Billable:     status.Pricing.Billable,
+			PricingModel: status.Pricing.PricingModel,
+			Category:     string(status.Pricing.Category),
+		}
+	}
+	return details
+}
+
+// statusError returns the title, code and message of the first error of a status.
+func statusError(status Status) (string, int, string) {
+	if len(status.Errors) == 0 {
+		return "", 0, ""
+	}
+	return status.Errors[0].Title, status.Errors[0].Code, status.Errors[0].Message
+}
+
+// messageReferral returns the ad a message was sent from, nil if it was not sent from an ad.
+func messageReferral(message Message) *events.AdSource {
+	referral := message.Referral
+	if referral.SourceId == "" && referral.SourceUrl == "" && referral.CtwaCLId == "" {
+		return nil
+	}
+	mediaUrl := referral.ImageUrl
+	if mediaUrl == "" {
+		mediaUrl = referral.VideoUrl
+	}
+	return &events.AdSource{
+		Url:          referral.SourceUrl,
+		Id:           referral.SourceId,
+		Type:         events.AdInteractionSourceType(referral.SourceType),
+		Title:        referral.Headline,
+		Description:  referral.Body,
+		MediaUrl:     mediaUrl,
+		MediaType:    events.AdInteractionSourceMediaType(referral.MediaType),
+		ThumbnailUrl: referral.ThumbnailUrl,
+		CtwaClid:     referral.CtwaCLId,
+	}
+}
+
+// messageText returns the text of a message, or the caption of a media message.
+func messageText(message Message) string {
+	switch message.Type {
+	case NotificationMessageTypeText:
+		return message.Text.Body
+	case NotificationMessageTypeImage:
+		return message.Image.Caption
+	case NotificationMessageTypeVideo:
+		return message.Video.Caption
+	case NotificationMessageTypeDocument:
+		return message.Document.Caption
+	case NotificationMessageTypeButton:
+		return message.Button.Text
+	}
+	return ""
+}
+
+// publishUnknownMessage publishes an UnknownEvent for a message the webhook does not support.
+func (wh *WebhookManager) publishUnknownMessage(payload HandleMessageSubscriptionEventPayload, message Message, baseMessageEvent events.BaseMessageEvent) {
+	wh.logger.Warn("unsupported webhook message", request_client.LogKeyPhoneNumberId, payload.PhoneNumber.Id, request_client.LogKeyMessageId, message.Id, "type", message.Type)
+	wh.publish(events.UnknownEventType, events.NewUnknownEvent(
+		baseMessageEvent,
+		string(message.Type),
+		webhookErrors(message.Errors),
+		message.Raw(),
+	))
+}
+
+// publishMessageError publishes an ErrorEvent for a message which could not be processed.
+func (wh *WebhookManager) publishMessageError(payload HandleMessageSubscriptionEventPayload, message Message, err error) {
+	wh.logger.Error("error processing webhook message", request_client.LogKeyPhoneNumberId, payload.PhoneNumber.Id, request_client.LogKeyMessageId, message.Id, "error", err)
+	wh.publish(events.ErrorEventType, events.NewErrorEvent(events.BaseSystemEvent{
+		Timestamp: message.Timestamp,
+	}, events.WebhookIssue{
+		BusinessAccountId: payload.BusinessAccountId,
+		PhoneNumber:       payload.PhoneNumber,
+		MessageId:         message.Id,
+		Errors:            webhookErrors(message.Errors),
+		Err:               err,
+		Raw:               message.Raw(),
+	}))
+}
+
+// sharedContacts converts the contact cards of a contacts message to contact components, which can be sent as is.
+func sharedContacts(contacts []SharedContact) []components.Contact {
+	converted := make([]components.Contact, len(contacts))
+	for i, contact := range contacts {
+		converted[i] = components.Contact{
+			Name: components.ContactName{
+				FormattedName: contact.Name.FormattedName,
+				FirstName:     contact.Name.FirstName,
+				LastName:      contact.Name.LastName,
+				MiddleName:    contact.Name.MiddleName,
+				Suffix:        contact.Name.Suffix,
+				Prefix:        contact.Name.Prefix,
+			},
+			Org: components.ContactOrg{
+				Company:    contact.Org.Company,
+				Title:      contact.Org.Title,
+				Department: contact.Org.Department,
+			},
+			Birthday: contact.Birthday,
+		}
+		for _, address := range contact.Addresses {
+			converted[i].Addresses = append(converted[i].Addresses, components.ContactAddress{
+				Street:      address.Street,
+				City:        address.City,
+				State:       address.State,
+				Zip:         address.Zip,
+				Country:     address.Country,
+				CountryCode: address.CountryCode,
+				Type:        components.AddressType(address.Type),
+			})
+		}
+		for _, contactUrl := range contact.Urls {
+			converted[i].Urls = append(converted[i].Urls, components.ContactUrl{
+				Url:  contactUrl.Url,
+				Type: components.UrlType(contactUrl.Type),
+			})
+		}
+		for _, email := range contact.Emails {
+			converted[i].Emails = append(converted[i].Emails, components.ContactEmail{
+				Email: email.Email,
+				Type:  components.EmailType(email.Type),
+			})
+		}
+		for _, phone := range contact.Phones {
+			converted[i].Phones = append(converted[i].Phones, components.ContactPhone{
+				Phone: phone.Phone,
+				WaId:  phone.WaId,
+				Type:  components.PhoneType(phone.Type),
+			})
+		}
+	}
+	return converted
+}
+
+// webhookErrors converts the errors of a notification to the errors of the events.
+func webhookErrors(errs []Error) []events.WebhookError {
+	if len(errs) == 0 {
+		return nil
+	}
+	webhookErrors := make([]events.WebhookError, len(errs))
+	for i, err := range errs {
+		webhookErrors[i] = events.WebhookError{
+			Code:    err.Code,
+			Title:   err.Title,
+			Message: err.Message,
+			Details: err.ErrorData.Details,
+			Href:    err.Href,
+		}
+	}
+	return webhookErrors
+}
+
+// changeValueError is returned when the value of a change can not be decoded, the notification is then rejected.
+type changeValueError struct {
+	field WebhookFieldEnum
+	err   error
+}
+
+func (err *changeValueError) Error() string {
+	return fmt.Sprintf("Invalid %s value JSON: %v", err.field, err.err)
+}
+
+func (err *changeValueError) Unwrap() error {
+	return err.err
+}
+
+// decodeChangeValue decodes the value of a change into value.
+func decodeChangeValue(change Change, value interface{}) error {
+	valueBytes, err := json.Marshal(change.Value)
+	if err != nil {
+		return fmt.Errorf("error marshaling %s value: %w", change.Field, err)
+	}
+	if err := json.Unmarshal(valueBytes, value); err != nil {
+		return &changeValueError{field: change.Field, err: err}
+	}
+	return nil
+}
+
+// processBusinessAccountChange publishes the event of a change of a business account field,
+// the fields the webhook does not handle are ignored.
+func (wh *WebhookManager) processBusinessAccountChange(entry Entry, change Change) error {
+	baseEvent := events.BaseBusinessAccountEvent{
+		BusinessAccountId: entry.Id,
+	}
+	if entry.Time != nil {
+		baseEvent.Timestamp = entry.Time.String()
+	}
+
+	switch change.Field {
+	case WebhookFieldEnumAccountAlerts:
+		var value AccountAlertsValue
+		if err := decodeChangeValue(change, &value); err != nil {
+			return err
+		}
+		return wh.handleAccountAlertsSubscriptionEvents(baseEvent, value)
+	case WebhookFieldEnumAccountReview:
+		var value AccountReviewUpdateValue
+		if err := decodeChangeValue(change, &value); err != nil {
+			return err
+		}
+		return wh.handleAccountReviewSubscriptionEvents(baseEvent, value)
+	case WebhookFieldEnumAccountUpdate:
+		var value AccountUpdateValue
+		if err := decodeChangeValue(change, &value); err != nil {
+			return err
+		}
+		return wh.handleAccountUpdateSubscriptionEvents(baseEvent, value)
+	case WebhookFieldEnumBusinessCapability:
+		var value BusinessCapabilityUpdateValue
+		if err := decodeChangeValue(change, &value); err != nil {
+			return err
+		}
+		return wh.handleBusinessCapabilitySubscriptionEvents(baseEvent, value)
+	case WebhookFieldEnumMessageTemplateQuality:
+		var value TemplateQualityUpdateValue
+		if err := decodeChangeValue(change, &value); err != nil {
+			return err
+		}
+		return wh.handleMessageTemplateQualitySubscriptionEvents(baseEvent, value)
+	case WebhookFieldEnumMessageTemplateStatus:
+		var value TemplateStatusUpdateValue
+		if err := decodeChangeValue(change, &value); err != nil {
+			return err
+		}
+		return wh.handleMessageTemplateStatusSubscriptionEvents(baseEvent, value)
+	case WebhookFieldEnumPhoneNumberName:
+		var value PhoneNumberNameUpdateValue
+		if err := decodeChangeValue(change, &value); err != nil {
+			return err
+		}
+		return wh.handlePhoneNumberNameSubscriptionEvents(baseEvent, value)
+	case WebhookFieldEnumPhoneNumberQuality:
+		var value PhoneNumberQualityUpdateValue
+		if err := decodeChangeValue(change, &value); err != nil {
+			return err
+		}
+		return wh.handlePhoneNumberQualitySubscriptionEvents(baseEvent, value)
+	case WebhookFieldEnumSecurity:
+		var value SecurityValue
+		if err := decodeChangeValue(change, &value); err != nil {
+			return err
+		}
+		return wh.handleSecuritySubscriptionEvents(baseEvent, value)
+	case WebhookFieldEnumTemplateCategoryUpdate:
+		var value TemplateCategoryUpdateValue
+		if err := decodeChangeValue(change, &value); err != nil {
+			return err
+		}
+		return wh.handleTemplateCategoryUpdateSubscriptionEvents(baseEvent, value)
+	case WebhookFieldEnumFlows:
+		var value FlowsValue
+		if err := decodeChangeValue(change, &value); err != nil {
+			return err
+		}
+		return wh.handleFlowsSubscriptionEvents(baseEvent, value)
+	default:
+		wh.logger.Debug("ignoring unhandled webhook field", "field", change.Field)
+		return nil
+	}
+}
+
 func (wh *WebhookManager) handleAccountAlertsSubscriptionEvents(baseEvent events.BaseBusinessAccountEvent, value AccountAlertsValue) error {
-	wh.EventManager.Publish(events.AccountAlertsEventType, events.NewAccountAlertEvent(
+	wh.publish(events.AccountAlertsEventType, events.NewAccountAlertEvent(
 		&baseEvent,
 		value.EntityType,
 		value.EntityId,
@@ -698,42 +953,64 @@ func (wh *WebhookManager) handleAccountAlertsSubscriptionEvents(baseEvent events
 	return nil
 }
 
-func (wh *WebhookManager) handleSecuritySubscriptionEvents(value SecurityValue) {
-	wh.EventManager.Publish(events.AccountAlertsEventType, events.SecurityEvent{})
-
+func (wh *WebhookManager) handleSecuritySubscriptionEvents(baseEvent events.BaseBusinessAccountEvent, value SecurityValue) error {
+	wh.publish(events.SecurityEventType, events.NewSecurityEvent(
+		&baseEvent,
+		value.DisplayPhoneNumber,
+		value.Event,
+		value.Requester,
+	))
+	return nil
 }
 
-func (wh *WebhookManager) handleAccountUpdateSubscriptionEvents(baseEvent events.BaseBusinessAccountEvent, value AccountUpdateValue) {
-
-	wh.EventManager.Publish(events.AccountAlertsEventType, events.NewAccountUpdateEvent(
+func (wh *WebhookManager) handleAccountUpdateSubscriptionEvents(baseEvent events.BaseBusinessAccountEvent, value AccountUpdateValue) error {
+	event := events.NewAccountUpdateEvent(
 		&baseEvent,
 		events.AccountUpdateEventEnum(value.Event),
 		value.PhoneNumber,
-	))
-
+	)
+	if value.BanInfo != nil {
+		event.BanInfo = &events.BanInfo{
+			WabaBanState: value.BanInfo.WabaBanState,
+			WabaBanDate:  value.BanInfo.WabaBanDate,
+		}
+	}
+	if value.ViolationInfo != nil {
+		event.ViolationInfo = &events.ViolationInfo{
+			ViolationType: value.ViolationInfo.ViolationType,
+		}
+	}
+	for _, restriction := range value.RestrictionInfo {
+		event.RestrictionInfo = append(event.RestrictionInfo, events.RestrictionInfo{
+			RestrictionType: restriction.RestrictionType,
+			Expiration:      restriction.Expiration,
+		})
+	}
+	wh.publish(events.AccountUpdateEventType, event)
+	return nil
 }
 
 func (wh *WebhookManager) handleAccountReviewSubscriptionEvents(baseEvent events.BaseBusinessAccountEvent, value AccountReviewUpdateValue) error {
-	wh.EventManager.Publish(events.AccountAlertsEventType, events.NewAccountReviewUpdateEvent(
+	wh.publish(events.AccountReviewUpdateEventType, events.NewAccountReviewUpdateEvent(
 		&baseEvent,
 		events.AccountReviewUpdateEventEnum(value.Decision),
 	))
 	return nil
-
 }
 
 func (wh *WebhookManager) handleBusinessCapabilitySubscriptionEvents(baseEvent events.BaseBusinessAccountEvent, value BusinessCapabilityUpdateValue) error {
-	wh.EventManager.Publish(events.AccountAlertsEventType, events.NewBusinessCapabilityUpdateEvent(
+	event := events.NewBusinessCapabilityUpdateEvent(
 		&baseEvent,
 		int64(value.MaxDailyConversationPerPhone),
 		int64(value.MaxPhoneNumbersPerBusiness),
-	))
+	)
+	event.MaxDailyConversationsPerBusiness = int64(value.MaxDailyConversationsPerBusiness)
+	wh.publish(events.BusinessCapabilityUpdateEventType, event)
 	return nil
-
 }
 
 func (wh *WebhookManager) handleMessageTemplateQualitySubscriptionEvents(baseEvent events.BaseBusinessAccountEvent, value TemplateQualityUpdateValue) error {
-	wh.EventManager.Publish(events.AccountAlertsEventType, events.NewMessageTemplateQualityUpdateEvent(
+	wh.publish(events.MessageTemplateQualityUpdateEventType, events.NewMessageTemplateQualityUpdateEvent(
 		&baseEvent,
 		events.MessageTemplateQualityUpdateQualityScoreEnum(value.PreviousQualityScore),
 		events.MessageTemplateQualityUpdateQualityScoreEnum(value.NewQualityScore),
@@ -741,53 +1018,134 @@ func (wh *WebhookManager) handleMessageTemplateQualitySubscriptionEvents(baseEve
 		value.MessageTemplateName,
 		value.MessageTemplateLanguage,
 	))
-
 	return nil
-
 }
 
 func (wh *WebhookManager) handleMessageTemplateStatusSubscriptionEvents(baseEvent events.BaseBusinessAccountEvent, value TemplateStatusUpdateValue) error {
-	wh.EventManager.Publish(events.AccountAlertsEventType, events.NewMessageTemplateStatusUpdateEvent(
+	event := events.NewMessageTemplateStatusUpdateEvent(
 		&baseEvent,
 		events.MessageTemplateStatusUpdateEventEnum(value.Event),
 		value.MessageTemplateId,
 		value.MessageTemplateName,
 		value.MessageTemplateLanguage,
 		events.MessageTemplateStatusUpdateReason(value.Reason),
-	))
+	)
+	if value.DisableInfo != nil {
+		event.DisableInfo = &events.MessageTemplateStatusUpdateDisableInfo{
+			DisableDate: value.DisableInfo.DisableDate,
+		}
+	}
+	if value.OtherInfo != nil {
+		event.OtherInfo = &events.MessageTemplateStatusUpdateOtherInfo{
+			Title:       value.OtherInfo.Title,
+			Description: value.OtherInfo.Description,
+		}
+	}
+	wh.publish(events.MessageTemplateStatusUpdateEventType, event)
 	return nil
-
 }
 
 func (wh *WebhookManager) handlePhoneNumberNameSubscriptionEvents(baseEvent events.BaseBusinessAccountEvent, value PhoneNumberNameUpdateValue) error {
-	wh.EventManager.Publish(events.AccountAlertsEventType, events.NewPhoneNumberNameUpdateEvent(
+	var rejectionReason *string
+	if value.RejectionReason != "" {
+		rejectionReason = &value.RejectionReason
+	}
+	wh.publish(events.PhoneNumberNameUpdateEventType, events.NewPhoneNumberNameUpdateEvent(
 		&baseEvent,
 		value.DisplayPhoneNumber,
 		value.RequestedVerifiedName,
 		value.Decision,
-		&value.RejectionReason,
+		rejectionReason,
 	))
 	return nil
 }
 
 func (wh *WebhookManager) handlePhoneNumberQualitySubscriptionEvents(baseEvent events.BaseBusinessAccountEvent, value PhoneNumberQualityUpdateValue) error {
-	wh.EventManager.Publish(events.AccountAlertsEventType, events.NewPhoneNumberQualityUpdateEvent(
+	event := events.NewPhoneNumberQualityUpdateEvent(
 		&baseEvent,
 		value.DisplayPhoneNumber,
 		events.PhoneNumberUpdateEventEnum(value.Event),
 		events.PhoneNumberQualityUpdateCurrentLimitEnum(value.CurrentLimit),
-	))
+	)
+	event.OldLimit = events.PhoneNumberQualityUpdateCurrentLimitEnum(value.OldLimit)
+	wh.publish(events.PhoneNumberQualityUpdateEventType, event)
 	return nil
 }
 
 func (wh *WebhookManager) handleTemplateCategoryUpdateSubscriptionEvents(baseEvent events.BaseBusinessAccountEvent, value TemplateCategoryUpdateValue) error {
-	wh.EventManager.Publish(events.AccountAlertsEventType, events.NewMessageTemplateCategoryUpdateEvent(
+	event := events.NewMessageTemplateCategoryUpdateEvent(
 		&baseEvent,
 		value.MessageTemplateId,
 		value.MessageTemplateName,
 		value.MessageTemplateLanguage,
 		events.MessageTemplateCategoryEnum(value.PreviousCategory),
 		events.MessageTemplateCategoryEnum(value.NewCategory),
-	))
+	)
+	event.CorrectCategory = events.MessageTemplateCategoryEnum(value.CorrectCategory)
+	wh.publish(events.TemplateCategoryUpdateEventType, event)
 	return nil
 }
+
+func (wh *WebhookManager) handleFlowsSubscriptionEvents(baseEvent events.BaseBusinessAccountEvent, value FlowsValue) error {
+	baseFlowEvent := events.NewBaseFlowEvent(&baseEvent, value.FlowId, value.Message)
+	alertState := events.FlowAlertStateEnum(value.AlertState)
+	switch value.Event {
+	case FlowsEventEnumFlowStatusChange:
+		wh.publish(events.FlowStatusChangeEventType, events.NewFlowStatusChangeEvent(
+			baseFlowEvent,
+			string(value.OldStatus),
+			string(value.NewStatus),
+		))
+	case FlowsEventEnumClientErrorRate:
+		wh.publish(events.FlowClientErrorRateEventType, events.NewFlowClientErrorRateEvent(
+			baseFlowEvent,
+			value.ErrorRate,
+			value.Threshold,
+			alertState,
+			flowErrors(value.Errors),
+		))
+	case FlowsEventEnumEndpointErrorRate:
+		wh.publish(events.FlowEndpointErrorRateEventType, events.NewFlowEndpointErrorRateEvent(
+			baseFlowEvent,
+			value.ErrorRate,
+			value.Threshold,
+			alertState,
+			flowErrors(value.Errors),
+		))
+	case FlowsEventEnumEndpointLatency:
+		wh.publish(events.FlowEndpointLatencyEventType, events.NewFlowEndpointLatencyEvent(
+			baseFlowEvent,
+			value.P50Latency,
+			value.P90Latency,
+			value.RequestsCount,
+			int64(value.Threshold),
+			alertState,
+		))
+	case FlowsEventEnumEndpointAvailability:
+		wh.publish(events.FlowEndpointAvailabilityEventType, events.NewFlowEndpointAvailabilityEvent(
+			baseFlowEvent,
+			value.Availability,
+			value.Threshold,
+			alertState,
+		))
+	default:
+		wh.logger.Debug("ignoring unhandled flows event", "event", value.Event, "flow_id", value.FlowId)
+	}
+	return nil
+}
+
+// flowErrors converts the errors of a flows notification to their event counterpart.
+func flowErrors(errs []FlowsError) []events.FlowError {
+	if len(errs) == 0 {
+		return nil
+	}
+	flowErrors := make([]events.FlowError, len(errs))
+	for i, err := range errs {
+		flowErrors[i] = events.FlowError{
+			ErrorType:  err.ErrorType,
+			ErrorRate:  err.ErrorRate,
+			ErrorCount: err.ErrorCount,
+		}
+	}
+	return flowErrors
+}
@@ -0,0 +1,609 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/gTahidi/wapi.go/internal/request_client"
+	"github.com/gTahidi/wapi.go/pkg/components"
+	"github.com/gTahidi/wapi.go/pkg/events"
+)
+
+// messagesNotification returns a notification of the messages field whose value holds the metadata and the
+// profile of textNotification along with fields, the JSON of its messages, statuses or errors.
+func messagesNotification(fields string) []byte {
+	return []byte(`{
+		"object": "whatsapp_business_account",
+		"entry": [{
+			"id": "102290129340398",
+			"changes": [{
+				"field": "messages",
+				"value": {
+					"messaging_product": "whatsapp",
+					"metadata": {"display_phone_number": "15550783881", "phone_number_id": "106540352242922"},
+					"contacts": [{"profile": {"name": "Sheena Nelson"}, "wa_id": "16505551234"}],
+					` + fields + `
+				}
+			}]
+		}]
+	}`)
+}
+
+// receiveEvents delivers a notification to a webhook and returns the events of eventTypes it published.
+func receiveEvents(t *testing.T, notification []byte, eventTypes ...events.EventType) map[events.EventType][]events.BaseEvent {
+	t.Helper()
+	eventManager := NewEventManager()
+	channels := map[events.EventType]chan ChannelEvent{}
+	for _, eventType := range eventTypes {
+		channel, err := eventManager.Subscribe(eventType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		channels[eventType] = channel
+	}
+	wh := NewWebhook(&WebhookManagerConfig{
+		Secret:       "secret",
+		EventManager: eventManager,
+		Requester:    *request_client.NewRequestClient("token"),
+	})
+	if response := wh.receiveNotification(context.Background(), notification, http.Header{}); response.status != http.StatusOK {
+		t.Fatalf("got status %d (%s), want %d", response.status, response.body, http.StatusOK)
+	}
+
+	published := map[events.EventType][]events.BaseEvent{}
+	for eventType, channel := range channels {
+		for len(channel) > 0 {
+			published[eventType] = append(published[eventType], (<-channel).Data)
+		}
+	}
+	return published
+}
+
+// checkPublished fails the test if the number of events published for a type differs from want.
+func checkPublished(t *testing.T, published map[events.EventType][]events.BaseEvent, want map[events.EventType]int) {
+	t.Helper()
+	for eventType, typeEvents := range published {
+		if len(typeEvents) != want[eventType] {
+			t.Errorf("got %d %s events, want %d", len(typeEvents), eventType, want[eventType])
+		}
+	}
+	for eventType, count := range want {
+		if _, ok := published[eventType]; !ok && count > 0 {
+			t.Errorf("got no %s event, want %d", eventType, count)
+		}
+	}
+}
+
+// rawMessageId returns the id held by the JSON of a message.
+func rawMessageId(t *testing.T, raw json.RawMessage) string {
+	t.Helper()
+	var message struct {
+		Id string `json:"id"`
+	}
+	if err := json.Unmarshal(raw, &message); err != nil {
+		t.Fatalf("error decoding raw message %q: %v", raw, err)
+	}
+	return message.Id
+}
+
+func TestWebhookPublishesUnknownErrorAndWarnEvents(t *testing.T) {
+	eventTypes := []events.EventType{
+		events.UnknownEventType,
+		events.ErrorEventType,
+		events.WarnEventType,
+		events.TextMessageEventType,
+		events.ReactionMessageEventType,
+		events.ReplyButtonInteractionEventType,
+	}
+	tests := []struct {
+		name   string
+		fields string
+		want   map[events.EventType]int
+		check  func(t *testing.T, published map[events.EventType][]events.BaseEvent)
+	}{
+		{
+			name: "unknown message",
+			fields: `"messages": [{
+				"from": "16505551234", "id": "wamid.unknown", "timestamp": "1749416383", "type": "unknown",
+				"errors": [{"code": 131051, "title": "Message type unknown", "message": "Message type unknown", "error_data": {"details": "Message type is currently not supported."}}]
+			}]`,
+			want: map[events.EventType]int{events.UnknownEventType: 1},
+			check: func(t *testing.T, published map[events.EventType][]events.BaseEvent) {
+				unknown := published[events.UnknownEventType][0].(*events.UnknownEvent)
+				if unknown.MessageType != "unknown" || unknown.MessageId != "wamid.unknown" || unknown.From != "16505551234" {
+					t.Errorf("got message %q of type %q from %q", unknown.MessageId, unknown.MessageType, unknown.From)
+				}
+				want := events.WebhookError{Code: 131051, Title: "Message type unknown", Message: "Message type unknown", Details: "Message type is currently not supported."}
+				if len(unknown.Errors) != 1 || unknown.Errors[0] != want {
+					t.Errorf("got errors %+v, want %+v", unknown.Errors, want)
+				}
+				if id := rawMessageId(t, unknown.Raw); id != "wamid.unknown" {
+					t.Errorf("got raw message %q, want the JSON of the message", unknown.Raw)
+				}
+			},
+		},
+		{
+			name: "unsupported message",
+			fields: `"messages": [{
+				"from": "16505551234", "id": "wamid.unsupported", "timestamp": "1749416383", "type": "unsupported",
+				"errors": [{"code": 131051, "title": "Message type unknown"}]
+			}]`,
+			want: map[events.EventType]int{events.UnknownEventType: 1},
+		},
+		{
+			name: "message type added by a later api version",
+			fields: `"messages": [{
+				"from": "16505551234", "id": "wamid.call", "timestamp": "1749416383", "type": "call",
+				"call": {"id": "call-1"}
+			}]`,
+			want: map[events.EventType]int{events.UnknownEventType: 1},
+			check: func(t *testing.T, published map[events.EventType][]events.BaseEvent) {
+				unknown := published[events.UnknownEventType][0].(*events.UnknownEvent)
+				var raw struct {
+					Call struct {
+						Id string `json:"id"`
+					} `json:"call"`
+				}
+				if err := json.Unmarshal(unknown.Raw, &raw); err != nil || raw.Call.Id != "call-1" {
+					t.Errorf("got raw message %q, want the content of the call", unknown.Raw)
+				}
+			},
+		},
+		{
+			name: "unknown interactive reply",
+			fields: `"messages": [{
+				"from": "16505551234", "id": "wamid.interactive", "timestamp": "1749416383", "type": "interactive",
+				"interactive": {"type": "call_permission_reply", "call_permission_reply": {"response": "accept"}}
+			}]`,
+			want: map[events.EventType]int{events.UnknownEventType: 1},
+			check: func(t *testing.T, published map[events.EventType][]events.BaseEvent) {
+				if unknown := published[events.UnknownEventType][0].(*events.UnknownEvent); unknown.MessageType != "interactive" {
+					t.Errorf("got message type %q, want interactive", unknown.MessageType)
+				}
+			},
+		},
+		{
+			name: "message with errors",
+			fields: `"messages": [{
+				"from": "16505551234", "id": "wamid.text", "timestamp": "1749416383", "type": "text",
+				"text": {"body": "Hello"},
+				"errors": [{"code": 131060, "title": "Media content unavailable"}]
+			}]`,
+			want: map[events.EventType]int{events.WarnEventType: 1, events.TextMessageEventType: 1},
+			check: func(t *testing.T, published map[events.EventType][]events.BaseEvent) {
+				warn := published[events.WarnEventType][0].(*events.WarnEvent)
+				if warn.MessageId != "wamid.text" || warn.PhoneNumber.Id != "106540352242922" || warn.BusinessAccountId != "102290129340398" {
+					t.Errorf("got warning about message %q of %+v in %q", warn.MessageId, warn.PhoneNumber, warn.BusinessAccountId)
+				}
+				if len(warn.Errors) != 1 || warn.Errors[0].Code != 131060 {
+					t.Errorf("got errors %+v, want the error of the message", warn.Errors)
+				}
+			},
+		},
+		{
+			name: "message which can not be processed",
+			fields: `"messages": [{
+				"from": "16505551234", "id": "wamid.reaction", "timestamp": "1749416383", "type": "reaction",
+				"reaction": {"message_id": "wamid.reacted"}
+			}]`,
+			want: map[events.EventType]int{events.ErrorEventType: 1},
+			check: func(t *testing.T, published map[events.EventType][]events.BaseEvent) {
+				errorEvent := published[events.ErrorEventType][0].(*events.ErrorEvent)
+				if errorEvent.MessageId != "wamid.reaction" || errorEvent.Err == nil || errorEvent.ErrorMessage != errorEvent.Err.Error() {
+					t.Errorf("got error %q about message %q, want the error creating the reaction", errorEvent.ErrorMessage, errorEvent.MessageId)
+				}
+				if id := rawMessageId(t, errorEvent.Raw); id != "wamid.reaction" {
+					t.Errorf("got raw message %q, want the JSON of the message", errorEvent.Raw)
+				}
+			},
+		},
+		{
+			name:   "notification with errors",
+			fields: `"errors": [{"code": 131000, "title": "Something went wrong", "href": "https://developers.facebook.com/docs/whatsapp/cloud-api/support/error-codes/"}]`,
+			want:   map[events.EventType]int{events.ErrorEventType: 1},
+			check: func(t *testing.T, published map[events.EventType][]events.BaseEvent) {
+				errorEvent := published[events.ErrorEventType][0].(*events.ErrorEvent)
+				if errorEvent.MessageId != "" || len(errorEvent.Errors) != 1 || errorEvent.Errors[0].Href == "" {
+					t.Errorf("got errors %+v about message %q, want the errors of the notification", errorEvent.Errors, errorEvent.MessageId)
+				}
+				var value MessagesValue
+				if err := json.Unmarshal(errorEvent.Raw, &value); err != nil || value.Metadata.PhoneNumberId != "106540352242922" {
+					t.Errorf("got raw value %q, want the JSON of the notification value", errorEvent.Raw)
+				}
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			published := receiveEvents(t, messagesNotification(test.fields), eventTypes...)
+			checkPublished(t, published, test.want)
+			if test.check != nil && !t.Failed() {
+				test.check(t, published)
+			}
+		})
+	}
+}
+
+func TestWebhookParsesSharedContacts(t *testing.T) {
+	card := `{
+		"name": {"formatted_name": "Dr. John Q. Smith Jr.", "first_name": "John", "last_name": "Smith", "middle_name": "Q.", "suffix": "Jr.", "prefix": "Dr."},
+		"org": {"company": "Acme", "department": "Sales", "title": "Manager"},
+		"addresses": [{"street": "1 Hacker Way", "city": "Menlo Park", "state": "CA", "zip": "94025", "country": "United States", "country_code": "US", "type": "WORK"}],
+		"urls": [{"url": "https://www.example.com", "type": "WORK"}],
+		"emails": [{"email": "john@example.com", "type": "WORK"}],
+		"phones": [
+			{"phone": "+1 (650) 555-1234", "wa_id": "16505551234", "type": "CELL"},
+			{"phone": "+1 (650) 555-0000", "type": "HOME"}
+		],
+		"birthday": "1980-08-17"
+	}`
+	published := receiveEvents(t, messagesNotification(`"messages": [{
+		"from": "16505551234", "id": "wamid.contacts", "timestamp": "1749416383", "type": "contacts",
+		"contacts": [`+card+`, {"name": {"formatted_name": "Jane", "first_name": "Jane"}, "phones": [{"phone": "+1 650 555 9999", "type": "MAIN"}]}]
+	}]`), events.ContactMessageEventType, events.ErrorEventType)
+	checkPublished(t, published, map[events.EventType]int{events.ContactMessageEventType: 1})
+	if t.Failed() {
+		return
+	}
+
+	contacts := published[events.ContactMessageEventType][0].(*events.ContactsMessageEvent).Contacts.Contacts
+	want := []components.Contact{
+		{
+			Name:      components.ContactName{FormattedName: "Dr. John Q. Smith Jr.", FirstName: "John", LastName: "Smith", MiddleName: "Q.", Suffix: "Jr.", Prefix: "Dr."},
+			Org:       components.ContactOrg{Company: "Acme", Department: "Sales", Title: "Manager"},
+			Addresses: []components.ContactAddress{{Street: "1 Hacker Way", City: "Menlo Park", State: "CA", Zip: "94025", Country: "United States", CountryCode: "US", Type: components.WorkAddress}},
+			Urls:      []components.ContactUrl{{Url: "https://www.example.com", Type: "WORK"}},
+			Emails:    []components.ContactEmail{{Email: "john@example.com", Type: components.WorkEmail}},
+			Phones: []components.ContactPhone{
+				{Phone: "+1 (650) 555-1234", WaId: "16505551234", Type: "CELL"},
+				{Phone: "+1 (650) 555-0000", Type: "HOME"},
+			},
+			Birthday: "1980-08-17",
+		},
+		{
+			Name:   components.ContactName{FormattedName: "Jane", FirstName: "Jane"},
+			Phones: []components.ContactPhone{{Phone: "+1 650 555 9999", Type: "MAIN"}},
+		},
+	}
+	if !reflect.DeepEqual(contacts, want) {
+		t.Errorf("got contacts %+v, want %+v", contacts, want)
+	}
+
+	// a received card is forwarded unchanged
+	message, err := components.NewContactMessage(contacts[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := message.ToJson(components.ApiCompatibleJsonConverterConfigs{SendToPhoneNumber: "16505551234"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var forwarded struct {
+		Contacts []interface{} `json:"contacts"`
+	}
+	var received interface{}
+	if err := json.Unmarshal(body, &forwarded); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(card), &received); err != nil {
+		t.Fatal(err)
+	}
+	if len(forwarded.Contacts) != 1 || !reflect.DeepEqual(forwarded.Contacts[0], received) {
+		t.Errorf("got forwarded contacts %s, want the received card", body)
+	}
+}
+
+func TestWebhookPublishesFlowCompletionEvents(t *testing.T) {
+	// interactiveNotification returns a notification of a reply to a flow message
+	interactiveNotification := func(interactive string) []byte {
+		return messagesNotification(`"messages": [{
+			"from": "16505551234", "id": "wamid.interactive", "timestamp": "1749416383", "type": "interactive",
+			"context": {"from": "15550783881", "id": "wamid.flow"},
+			"interactive": ` + interactive + `
+		}]`)
+	}
+	eventTypes := []events.EventType{events.FlowCompletionEventType, events.ReplyButtonInteractionEventType, events.ErrorEventType}
+	tests := []struct {
+		name        string
+		interactive string
+		want        map[events.EventType]int
+	}{
+		{
+			name:        "completed flow",
+			interactive: `{"type": "nfm_reply", "nfm_reply": {"name": "flow", "body": "Sent", "response_json": "{\"flow_token\": \"token-1\", \"size\": \"M\", \"quantity\": 2}"}}`,
+			want:        map[events.EventType]int{events.FlowCompletionEventType: 1},
+		},
+		{
+			name:        "invalid response",
+			interactive: `{"type": "nfm_reply", "nfm_reply": {"name": "flow", "body": "Sent", "response_json": "{\"flow_token\""}}`,
+			want:        map[events.EventType]int{events.ErrorEventType: 1},
+		},
+		{
+			name:        "button reply",
+			interactive: `{"type": "button_reply", "button_reply": {"id": "yes", "title": "Yes"}}`,
+			want:        map[events.EventType]int{events.ReplyButtonInteractionEventType: 1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checkPublished(t, receiveEvents(t, interactiveNotification(test.interactive), eventTypes...), test.want)
+		})
+	}
+
+	published := receiveEvents(t, interactiveNotification(tests[0].interactive), events.FlowCompletionEventType)
+	flowCompletion := published[events.FlowCompletionEventType][0].(*events.FlowCompletionEvent)
+	if flowCompletion.FlowToken != "token-1" || flowCompletion.Name != "flow" || flowCompletion.Body != "Sent" {
+		t.Errorf("got flow token %q, name %q and body %q", flowCompletion.FlowToken, flowCompletion.Name, flowCompletion.Body)
+	}
+	if flowCompletion.Context.RepliedToMessageId != "wamid.flow" {
+		t.Errorf("got reply to %q, want the flow message", flowCompletion.Context.RepliedToMessageId)
+	}
+	if flowCompletion.Response["size"] != "M" || flowCompletion.Response["quantity"] != float64(2) {
+		t.Errorf("got response %v, want the submitted data", flowCompletion.Response)
+	}
+	var response struct {
+		Size     string `json:"size"`
+		Quantity int    `json:"quantity"`
+	}
+	if err := flowCompletion.DecodeResponse(&response); err != nil || response.Size != "M" || response.Quantity != 2 {
+		t.Errorf("DecodeResponse() = %v with %+v, want the submitted data", err, response)
+	}
+}
+
+func TestWebhookPublishesAdInteractionEvents(t *testing.T) {
+	referral := `"referral": {
+		"source_url": "https://fb.me/3cr4Wqqkv", "source_type": "ad", "source_id": "120201234567890",
+		"headline": "Summer sale", "body": "Chat with us", "media_type": "%s", "%s": "https://example.com/ad-media",
+		"thumbnail_url": "https://example.com/ad-thumbnail", "ctwa_clid": "ARAkLkA8rmlFeiCktEJQ"
+	}`
+	adSource := func(mediaType events.AdInteractionSourceMediaType) *events.AdSource {
+		return &events.AdSource{
+			Url:          "https://fb.me/3cr4Wqqkv",
+			Id:           "120201234567890",
+			Type:         events.AdInteractionSourceTypeAd,
+			Title:        "Summer sale",
+			Description:  "Chat with us",
+			MediaUrl:     "https://example.com/ad-media",
+			MediaType:    mediaType,
+			ThumbnailUrl: "https://example.com/ad-thumbnail",
+			CtwaClid:     "ARAkLkA8rmlFeiCktEJQ",
+		}
+	}
+	tests := []struct {
+		name        string
+		message     string
+		messageType events.EventType
+		want        *events.AdSource
+		wantText    string
+	}{
+		{
+			name:        "text from an image ad",
+			message:     `"type": "text", "text": {"body": "Is it still on sale?"}, ` + fmt.Sprintf(referral, "image", "image_url"),
+			messageType: events.TextMessageEventType,
+			want:        adSource(events.AdInteractionSourceMediaTypeImage),
+			wantText:    "Is it still on sale?",
+		},
+		{
+			name:        "image from a video ad",
+			message:     `"type": "image", "image": {"id": "1234", "mime_type": "image/jpeg", "caption": "This one"}, ` + fmt.Sprintf(referral, "video", "video_url"),
+			messageType: events.ImageMessageEventType,
+			want:        adSource(events.AdInteractionSourceMediaTypeVideo),
+			wantText:    "This one",
+		},
+		{
+			name:        "text not from an ad",
+			message:     `"type": "text", "text": {"body": "Hello"}`,
+			messageType: events.TextMessageEventType,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			published := receiveEvents(t, messagesNotification(`"messages": [{
+				"from": "16505551234", "id": "wamid.ad", "timestamp": "1749416383", `+test.message+`
+			}]`), events.AdInteractionEventType, test.messageType)
+			want := map[events.EventType]int{test.messageType: 1}
+			if test.want != nil {
+				want[events.AdInteractionEventType] = 1
+			}
+			checkPublished(t, published, want)
+			if t.Failed() {
+				return
+			}
+
+			var messageReferral *events.AdSource
+			switch event := published[test.messageType][0].(type) {
+			case *events.TextMessageEvent:
+				messageReferral = event.Referral
+			case *events.ImageMessageEvent:
+				messageReferral = event.Referral
+			}
+			if !reflect.DeepEqual(messageReferral, test.want) {
+				t.Errorf("got referral %+v on the %s event, want %+v", messageReferral, test.messageType, test.want)
+			}
+			if test.want == nil {
+				return
+			}
+			adInteraction := published[events.AdInteractionEventType][0].(*events.AdInteractionEvent)
+			if adInteraction.AdSource != *test.want || adInteraction.Text != test.wantText || adInteraction.MessageId != "wamid.ad" {
+				t.Errorf("got ad source %+v with text %q for message %q, want %+v with text %q", adInteraction.AdSource, adInteraction.Text, adInteraction.MessageId, *test.want, test.wantText)
+			}
+		})
+	}
+}
+
+func TestWebhookPublishesProductInquiryEvents(t *testing.T) {
+	tests := []struct {
+		name        string
+		context     string
+		message     string
+		messageType events.EventType
+		wantText    string
+	}{
+		{
+			name:        "text about a product",
+			context:     `{"from": "15550783881", "id": "wamid.product", "referred_product": {"catalog_id": "194836987003835", "product_retailer_id": "sku-1"}}`,
+			message:     `"type": "text", "text": {"body": "Does it come in another color?"}`,
+			messageType: events.TextMessageEventType,
+			wantText:    "Does it come in another color?",
+		},
+		{
+			name:        "image about a product",
+			context:     `{"from": "15550783881", "id": "wamid.product", "referred_product": {"catalog_id": "194836987003835", "product_retailer_id": "sku-1"}}`,
+			message:     `"type": "image", "image": {"id": "1234", "mime_type": "image/jpeg", "caption": "Like this one?"}`,
+			messageType: events.ImageMessageEventType,
+			wantText:    "Like this one?",
+		},
+		{
+			name:        "reply to a message",
+			context:     `{"from": "15550783881", "id": "wamid.product"}`,
+			message:     `"type": "text", "text": {"body": "Thanks"}`,
+			messageType: events.TextMessageEventType,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			published := receiveEvents(t, messagesNotification(`"messages": [{
+				"from": "16505551234", "id": "wamid.inquiry", "timestamp": "1749416383",
+				"context": `+test.context+`, `+test.message+`
+			}]`), events.ProductInquiryEventType, test.messageType)
+			want := map[events.EventType]int{test.messageType: 1}
+			if test.wantText != "" {
+				want[events.ProductInquiryEventType] = 1
+			}
+			checkPublished(t, published, want)
+			if t.Failed() || test.wantText == "" {
+				return
+			}
+
+			inquiry := published[events.ProductInquiryEventType][0].(*events.ProductInquiryEvent)
+			if inquiry.CatalogId != "194836987003835" || inquiry.ProductId != "sku-1" || inquiry.Text != test.wantText {
+				t.Errorf("got product %q of catalog %q with text %q, want sku-1 of 194836987003835 with text %q", inquiry.ProductId, inquiry.CatalogId, inquiry.Text, test.wantText)
+			}
+			if inquiry.MessageId != "wamid.inquiry" || inquiry.From != "16505551234" || inquiry.Context.RepliedToMessageId != "wamid.product" {
+				t.Errorf("got message %q from %q replying to %q", inquiry.MessageId, inquiry.From, inquiry.Context.RepliedToMessageId)
+			}
+		})
+	}
+}
+
+// mustMarshal returns the JSON of v, the details of the events holding pointers.
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	body, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+// statusEventDetails returns the billing details and the callback data of a message status event.
+func statusEventDetails(t *testing.T, event events.BaseEvent) events.MessageStatusDetails {
+	t.Helper()
+	switch event := event.(type) {
+	case *events.MessageSentEvent:
+		return event.MessageStatusDetails
+	case *events.MessageDeliveredEvent:
+		return event.MessageStatusDetails
+	case *events.MessageReadEvent:
+		return event.MessageStatusDetails
+	case *events.MessageFailedEvent:
+		return event.MessageStatusDetails
+	case *events.MessageUndeliveredEvent:
+		return event.MessageStatusDetails
+	}
+	t.Fatalf("got event %T, want a message status event", event)
+	return events.MessageStatusDetails{}
+}
+
+func TestWebhookPublishesMessageStatusDetails(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    string
+		eventType events.EventType
+		want      events.MessageStatusDetails
+	}{
+		{
+			name: "sent opening a conversation",
+			status: `"status": "sent",
+				"conversation": {"id": "ee2e1a2d5f6bd2e0a4ab0a3b4c5d6e7f", "expiration_timestamp": "1749502783", "origin": {"type": "marketing"}},
+				"pricing": {"billable": true, "pricing_model": "CBP", "category": "marketing"},
+				"biz_opaque_callback_data": "campaign-7"`,
+			eventType: events.MessageSentEventType,
+			want: events.MessageStatusDetails{
+				Conversation:          &events.MessageStatusConversation{Id: "ee2e1a2d5f6bd2e0a4ab0a3b4c5d6e7f", OriginType: "marketing", ExpirationTimestamp: "1749502783"},
+				Pricing:               &events.MessageStatusPricing{Billable: true, PricingModel: "CBP", Category: "marketing"},
+				BizOpaqueCallbackData: "campaign-7",
+			},
+		},
+		{
+			name: "sent with the expiration in the origin",
+			status: `"status": "sent",
+				"conversation": {"id": "conversation-1", "origin": {"type": "utility", "expiration_timestamp": "1749502783"}},
+				"pricing": {"billable": true, "pricing_model": "CBP", "category": "utility"}`,
+			eventType: events.MessageSentEventType,
+			want: events.MessageStatusDetails{
+				Conversation: &events.MessageStatusConversation{Id: "conversation-1", OriginType: "utility", ExpirationTimestamp: "1749502783"},
+				Pricing:      &events.MessageStatusPricing{Billable: true, PricingModel: "CBP", Category: "utility"},
+			},
+		},
+		{
+			name: "delivered in a service conversation",
+			status: `"status": "delivered",
+				"conversation": {"id": "conversation-2", "origin": {"type": "service"}},
+				"pricing": {"billable": false, "pricing_model": "CBP", "category": "service"},
+				"biz_opaque_callback_data": "ticket-42"`,
+			eventType: events.MessageDeliveredEventType,
+			want: events.MessageStatusDetails{
+				Conversation:          &events.MessageStatusConversation{Id: "conversation-2", OriginType: "service"},
+				Pricing:               &events.MessageStatusPricing{PricingModel: "CBP", Category: "service"},
+				BizOpaqueCallbackData: "ticket-42",
+			},
+		},
+		{
+			name:      "read",
+			status:    `"status": "read", "biz_opaque_callback_data": "campaign-7"`,
+			eventType: events.MessageReadEventType,
+			want:      events.MessageStatusDetails{BizOpaqueCallbackData: "campaign-7"},
+		},
+		{
+			name: "failed",
+			status: `"status": "failed",
+				"errors": [{"code": 131047, "title": "Re-engagement message", "message": "Re-engagement message"}],
+				"biz_opaque_callback_data": "campaign-7"`,
+			eventType: events.MessageFailedEventType,
+			want:      events.MessageStatusDetails{BizOpaqueCallbackData: "campaign-7"},
+		},
+		{
+			name: "undelivered",
+			status: `"status": "undelivered",
+				"pricing": {"billable": false, "pricing_model": "PMP", "category": "marketing"}`,
+			eventType: events.MessageUndeliveredEventType,
+			want: events.MessageStatusDetails{
+				Pricing: &events.MessageStatusPricing{PricingModel: "PMP", Category: "marketing"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			published := receiveEvents(t, messagesNotification(`"statuses": [{
+				"id": "wamid.status", "timestamp": "1749416383", "recipient_id": "16505551234", `+test.status+`
+			}]`), test.eventType)
+			checkPublished(t, published, map[events.EventType]int{test.eventType: 1})
+			if t.Failed() {
+				return
+			}
+			if details := statusEventDetails(t, published[test.eventType][0]); !reflect.DeepEqual(details, test.want) {
+				t.Errorf("got details %s, want %s", mustMarshal(t, details), mustMarshal(t, test.want))
+			}
+			if failed, ok := published[test.eventType][0].(*events.MessageFailedEvent); ok {
+				if failed.MessageId != "wamid.status" || failed.SentTo != "16505551234" || failed.ErrorCode != 131047 || failed.FailReason != "Re-engagement message" {
+					t.Errorf("got message %q to %q failed with %d %q", failed.MessageId, failed.SentTo, failed.ErrorCode, failed.FailReason)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,451 @@
+package manager
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrWebhookQueueFull is returned by a bounded WebhookQueue which can not take more deliveries.
+var ErrWebhookQueueFull = errors.New("webhook queue is full")
+
+// ErrWebhookQueueClosed is returned by a WebhookQueue which was closed.
+var ErrWebhookQueueClosed = errors.New("webhook queue is closed")
+
+// WebhookDelivery is a notification received by the webhook in fast-ack mode, waiting to be processed.
+type WebhookDelivery struct {
+	// Id is assigned by the queue when the delivery is enqueued.
+	Id         uint64    `json:"id"`
+	Body       []byte    `json:"body"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// WebhookDeadLetter is a delivery which could not be processed after all its attempts.
+type WebhookDeadLetter struct {
+	Delivery WebhookDelivery `json:"delivery"`
+	Reason   string          `json:"reason"`
+	At       time.Time       `json:"at"`
+}
+
+// WebhookQueue holds the notifications acknowledged by the webhook in fast-ack mode until they are processed.
+// Dequeued deliveries stay owned by the queue until they are acked, dead-lettered or nacked, so that a durable
+// queue can hand them out again after a restart.
+type WebhookQueue interface {
+	// Enqueue stores the delivery, it must be durable when it returns for a durable queue.
+	Enqueue(ctx context.Context, delivery WebhookDelivery) error
+	// Dequeue blocks until a delivery is available, or ctx is done.
+	Dequeue(ctx context.Context) (WebhookDelivery, error)
+	// Ack marks the delivery as processed.
+	Ack(ctx context.Context, delivery WebhookDelivery) error
+	// DeadLetter marks the delivery as failed for good.
+	DeadLetter(ctx context.Context, deadLetter WebhookDeadLetter) error
+	// Nack gives back a dequeued delivery which was not processed, it is the next one to be dequeued.
+	Nack(ctx context.Context, delivery WebhookDelivery) error
+	// Len returns the number of deliveries waiting to be dequeued.
+	Len() int
+}
+
+// deliveryList is a FIFO of deliveries with a notification channel for the consumers waiting on it,
+// shared by the queues of the SDK.
+type deliveryList struct {
+	deliveries []WebhookDelivery
+	notify     chan struct{}
+}
+
+func newDeliveryList() deliveryList {
+	return deliveryList{notify: make(chan struct{}, 1)}
+}
+
+func (list *deliveryList) push(delivery WebhookDelivery) {
+	list.deliveries = append(list.deliveries, delivery)
+	list.signal()
+}
+
+// pushFront puts a delivery back at the head of the list.
+func (list *deliveryList) pushFront(delivery WebhookDelivery) {
+	list.deliveries = slices.Insert(list.deliveries, 0, delivery)
+	list.signal()
+}
+
+func (list *deliveryList) signal() {
+	select {
+	case list.notify <- struct{}{}:
+	default:
+	}
+}
+
+// pop removes the first delivery, waking up another consumer if there are more.
+func (list *deliveryList) pop() (WebhookDelivery, bool) {
+	if len(list.deliveries) == 0 {
+		return WebhookDelivery{}, false
+	}
+	delivery := list.deliveries[0]
+	list.deliveries[0] = WebhookDelivery{}
+	list.deliveries = list.deliveries[1:]
+	if len(list.deliveries) > 0 {
+		list.signal()
+	}
+	return delivery, true
+}
+
+// dequeue pops the first delivery, waiting for one while ctx is not done. The mutex guards the list,
+// popped is called with the delivery while it is held, if not nil.
+func (list *deliveryList) dequeue(ctx context.Context, mutex *sync.Mutex, popped func(WebhookDelivery)) (WebhookDelivery, error) {
+	for {
+		mutex.Lock()
+		delivery, ok := list.pop()
+		if ok && popped != nil {
+			popped(delivery)
+		}
+		mutex.Unlock()
+		if ok {
+			return delivery, nil
+		}
+		select {
+		case <-ctx.Done():
+			return WebhookDelivery{}, ctx.Err()
+		case <-list.notify:
+		}
+	}
+}
+
+// BoundedMemoryWebhookQueue is an in-memory WebhookQueue holding a bounded number of deliveries, which are lost
+// when the process exits. Unlike a ring buffer, it never drops the oldest deliveries to make room: Enqueue rejects
+// the new ones with ErrWebhookQueueFull once it is full, which the webhook answers with 503 so that Meta delivers
+// the notification again later.
+type BoundedMemoryWebhookQueue struct {
+	mutex       sync.Mutex
+	capacity    int
+	list        deliveryList
+	nextId      uint64
+	deadLetters []WebhookDeadLetter
+}
+
+// DefaultBoundedMemoryWebhookQueueCapacity is the capacity of a BoundedMemoryWebhookQueue when none is given.
+const DefaultBoundedMemoryWebhookQueueCapacity = 1000
+
+// NewBoundedMemoryWebhookQueue creates a new instance of BoundedMemoryWebhookQueue holding at most capacity
+// deliveries, DefaultBoundedMemoryWebhookQueueCapacity when capacity is not positive.
+func NewBoundedMemoryWebhookQueue(capacity int) *BoundedMemoryWebhookQueue {
+	if capacity <= 0 {
+		capacity = DefaultBoundedMemoryWebhookQueueCapacity
+	}
+	return &BoundedMemoryWebhookQueue{capacity: capacity, list: newDeliveryList()}
+}
+
+func (queue *BoundedMemoryWebhookQueue) Enqueue(ctx context.Context, delivery WebhookDelivery) error {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	if len(queue.list.deliveries) >= queue.capacity {
+		return ErrWebhookQueueFull
+	}
+	queue.nextId++
+	delivery.Id = queue.nextId
+	queue.list.push(delivery)
+	return nil
+}
+
+func (queue *BoundedMemoryWebhookQueue) Dequeue(ctx context.Context) (WebhookDelivery, error) {
+	return queue.list.dequeue(ctx, &queue.mutex, nil)
+}
+
+func (queue *BoundedMemoryWebhookQueue) Ack(ctx context.Context, delivery WebhookDelivery) error {
+	return nil
+}
+
+// Nack puts the delivery back at the head of the queue, even when the queue is full.
+func (queue *BoundedMemoryWebhookQueue) Nack(ctx context.Context, delivery WebhookDelivery) error {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	queue.list.pushFront(delivery)
+	return nil
+}
+
+// DeadLetter keeps the dead letter in memory, the oldest ones are dropped beyond the capacity of the queue.
+func (queue *BoundedMemoryWebhookQueue) DeadLetter(ctx context.Context, deadLetter WebhookDeadLetter) error {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	queue.deadLetters = append(queue.deadLetters, deadLetter)
+	if len(queue.deadLetters) > queue.capacity {
+		queue.deadLetters = queue.deadLetters[len(queue.deadLetters)-queue.capacity:]
+	}
+	return nil
+}
+
+func (queue *BoundedMemoryWebhookQueue) Len() int {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	return len(queue.list.deliveries)
+}
+
+// DeadLetters returns the deliveries which could not be processed.
+func (queue *BoundedMemoryWebhookQueue) DeadLetters() []WebhookDeadLetter {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	return append([]WebhookDeadLetter{}, queue.deadLetters...)
+}
+
+// journalRecord is a line of the journal of a FileWebhookQueue.
+type journalRecord struct {
+	Op       string           `json:"op"`
+	Delivery *WebhookDelivery `json:"delivery,omitempty"`
+	Id       uint64           `json:"id,omitempty"`
+}
+
+const (
+	journalOpEnqueue = "enqueue"
+	journalOpAck     = "ack"
+)
+
+// FileWebhookQueue is a WebhookQueue backed by an append-only journal file. Every delivery is written to the
+// journal before being acknowledged to Meta, and the deliveries which were not acked when the process exited
+// are replayed when the queue is opened again. Dead letters are appended to a separate file, the path of the
+// journal suffixed with ".dead".
+type FileWebhookQueue struct {
+	mutex   sync.Mutex
+	path    string
+	journal *os.File
+	list    deliveryList
+	nextId  uint64
+	// inFlight holds the deliveries dequeued and not acked yet, which must survive a compaction.
+	inFlight map[uint64]WebhookDelivery
+	// records is the number of lines of the journal.
+	records  int
+	deadFile *os.File
+}
+
+// NewFileWebhookQueue opens the journal at path, creating it if it does not exist, and queues the deliveries
+// left unprocessed by the previous run.
+func NewFileWebhookQueue(path string) (*FileWebhookQueue, error) {
+	queue := &FileWebhookQueue{path: path, list: newDeliveryList(), inFlight: map[uint64]WebhookDelivery{}}
+	if err := queue.load(); err != nil {
+		return nil, err
+	}
+	if err := queue.compact(); err != nil {
+		return nil, err
+	}
+	deadFile, err := os.OpenFile(path+".dead", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		queue.journal.Close()
+		return nil, fmt.Errorf("error opening webhook dead letter file: %w", err)
+	}
+	queue.deadFile = deadFile
+	return queue, nil
+}
+
+// load replays the journal, keeping the deliveries which were enqueued and not acked.
+func (queue *FileWebhookQueue) load() error {
+	file, err := os.Open(queue.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error opening webhook journal: %w", err)
+	}
+	defer file.Close()
+
+	pending := map[uint64]WebhookDelivery{}
+	order := []uint64{}
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			var record journalRecord
+			// a record which can not be read is skipped, it was truncated by a crash while being appended
+			if json.Unmarshal(line, &record) == nil {
+				switch {
+				case record.Op == journalOpEnqueue && record.Delivery != nil:
+					pending[record.Delivery.Id] = *record.Delivery
+					order = append(order, record.Delivery.Id)
+					queue.nextId = max(queue.nextId, record.Delivery.Id)
+				case record.Op == journalOpAck:
+					delete(pending, record.Id)
+				}
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	for _, id := range order {
+		if delivery, ok := pending[id]; ok {
+			queue.list.push(delivery)
+		}
+	}
+	return nil
+}
+
+// compact rewrites the journal with the unacked deliveries only, and opens it for appending.
+func (queue *FileWebhookQueue) compact() error {
+	if queue.journal != nil {
+		queue.journal.Close()
+		queue.journal = nil
+	}
+
+	deliveries := make([]WebhookDelivery, 0, len(queue.inFlight)+len(queue.list.deliveries))
+	for _, delivery := range queue.inFlight {
+		deliveries = append(deliveries, delivery)
+	}
+	slices.SortFunc(deliveries, func(a, b WebhookDelivery) int { return cmp.Compare(a.Id, b.Id) })
+	deliveries = append(deliveries, queue.list.deliveries...)
+
+	temporaryPath := queue.path + ".tmp"
+	file, err := os.Create(temporaryPath)
+	if err != nil {
+		return fmt.Errorf("error compacting webhook journal: %w", err)
+	}
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for index := range deliveries {
+		if err := encoder.Encode(journalRecord{Op: journalOpEnqueue, Delivery: &deliveries[index]}); err != nil {
+			file.Close()
+			return fmt.Errorf("error compacting webhook journal: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("error compacting webhook journal: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("error compacting webhook journal: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error compacting webhook journal: %w", err)
+	}
+	if err := os.Rename(temporaryPath, queue.path); err != nil {
+		return fmt.Errorf("error compacting webhook journal: %w", err)
+	}
+
+	queue.journal, err = os.OpenFile(queue.path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening webhook journal: %w", err)
+	}
+	queue.records = len(deliveries)
+	return nil
+}
+
+// append writes a record to the journal and syncs it to disk.
+func (queue *FileWebhookQueue) append(record journalRecord) error {
+	if queue.journal == nil {
+		return ErrWebhookQueueClosed
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("error encoding webhook journal record: %w", err)
+	}
+	if _, err := queue.journal.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("error writing webhook journal: %w", err)
+	}
+	if err := queue.journal.Sync(); err != nil {
+		return fmt.Errorf("error writing webhook journal: %w", err)
+	}
+	queue.records++
+	return nil
+}
+
+func (queue *FileWebhookQueue) Enqueue(ctx context.Context, delivery WebhookDelivery) error {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	queue.nextId++
+	delivery.Id = queue.nextId
+	if err := queue.append(journalRecord{Op: journalOpEnqueue, Delivery: &delivery}); err != nil {
+		return err
+	}
+	queue.list.push(delivery)
+	return nil
+}
+
+func (queue *FileWebhookQueue) Dequeue(ctx context.Context) (WebhookDelivery, error) {
+	return queue.list.dequeue(ctx, &queue.mutex, func(delivery WebhookDelivery) {
+		queue.inFlight[delivery.Id] = delivery
+	})
+}
+
+func (queue *FileWebhookQueue) Ack(ctx context.Context, delivery WebhookDelivery) error {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	if err := queue.append(journalRecord{Op: journalOpAck, Id: delivery.Id}); err != nil {
+		return err
+	}
+	delete(queue.inFlight, delivery.Id)
+	// compact once the journal is mostly made of processed deliveries
+	if queue.records > 2*(len(queue.inFlight)+len(queue.list.deliveries))+1024 {
+		return queue.compact()
+	}
+	return nil
+}
+
+// Nack puts the delivery back at the head of the queue, its enqueue record is still in the journal.
+func (queue *FileWebhookQueue) Nack(ctx context.Context, delivery WebhookDelivery) error {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	delete(queue.inFlight, delivery.Id)
+	queue.list.pushFront(delivery)
+	return nil
+}
+
+// DeadLetter appends the dead letter to the dead letter file, then acks the delivery.
+func (queue *FileWebhookQueue) DeadLetter(ctx context.Context, deadLetter WebhookDeadLetter) error {
+	queue.mutex.Lock()
+	line, err := json.Marshal(deadLetter)
+	if err == nil {
+		_, err = queue.deadFile.Write(append(line, '\n'))
+	}
+	queue.mutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("error writing webhook dead letter: %w", err)
+	}
+	return queue.Ack(ctx, deadLetter.Delivery)
+}
+
+func (queue *FileWebhookQueue) Len() int {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	return len(queue.list.deliveries)
+}
+
+// DeadLetters reads the deliveries which could not be processed from the dead letter file.
+func (queue *FileWebhookQueue) DeadLetters() ([]WebhookDeadLetter, error) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	file, err := os.Open(queue.path + ".dead")
+	if err != nil {
+		return nil, fmt.Errorf("error opening webhook dead letter file: %w", err)
+	}
+	defer file.Close()
+	deadLetters := []WebhookDeadLetter{}
+	decoder := json.NewDecoder(file)
+	for decoder.More() {
+		var deadLetter WebhookDeadLetter
+		if err := decoder.Decode(&deadLetter); err != nil {
+			return deadLetters, fmt.Errorf("error reading webhook dead letter file: %w", err)
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	return deadLetters, nil
+}
+
+// Close closes the files of the queue, the deliveries not acked yet are replayed when it is opened again.
+func (queue *FileWebhookQueue) Close() error {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	var errs []error
+	if queue.journal != nil {
+		errs = append(errs, queue.journal.Close())
+		queue.journal = nil
+	}
+	if queue.deadFile != nil {
+		errs = append(errs, queue.deadFile.Close())
+		queue.deadFile = nil
+	}
+	return errors.Join(errs...)
+}
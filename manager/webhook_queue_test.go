@@ -0,0 +1,219 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// enqueueDeliveries enqueues a delivery per body.
+func enqueueDeliveries(t *testing.T, queue WebhookQueue, bodies ...string) {
+	t.Helper()
+	for _, body := range bodies {
+		if err := queue.Enqueue(context.Background(), WebhookDelivery{Body: []byte(body), ReceivedAt: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// dequeueDeliveries dequeues count deliveries and returns them.
+func dequeueDeliveries(t *testing.T, queue WebhookQueue, count int) []WebhookDelivery {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	deliveries := []WebhookDelivery{}
+	for range count {
+		delivery, err := queue.Dequeue(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries
+}
+
+func deliveryBodies(deliveries []WebhookDelivery) []string {
+	bodies := []string{}
+	for _, delivery := range deliveries {
+		bodies = append(bodies, string(delivery.Body))
+	}
+	return bodies
+}
+
+func openFileWebhookQueue(t *testing.T, path string) *FileWebhookQueue {
+	t.Helper()
+	queue, err := NewFileWebhookQueue(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { queue.Close() })
+	return queue
+}
+
+func TestFileWebhookQueueReplaysUnackedDeliveries(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "journal")
+	queue := openFileWebhookQueue(t, path)
+	enqueueDeliveries(t, queue, "acked", "in flight", "dead", "queued")
+	deliveries := dequeueDeliveries(t, queue, 3)
+	if err := queue.Ack(ctx, deliveries[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := queue.DeadLetter(ctx, WebhookDeadLetter{Delivery: deliveries[2], Reason: "status 400: Invalid JSON", At: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	queue.Close()
+
+	// a record truncated by a crash while being appended is skipped
+	journal, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	journal.WriteString(`{"op":"enqueue","delivery":{"id":9`)
+	journal.Close()
+
+	queue = openFileWebhookQueue(t, path)
+	if got := queue.Len(); got != 2 {
+		t.Fatalf("got %d deliveries after a restart, want 2", got)
+	}
+	replayed := dequeueDeliveries(t, queue, 2)
+	if want := []string{"in flight", "queued"}; !slices.Equal(deliveryBodies(replayed), want) {
+		t.Errorf("got deliveries %v after a restart, want %v", deliveryBodies(replayed), want)
+	}
+	if replayed[0].Id != deliveries[1].Id {
+		t.Errorf("got id %d for the replayed delivery, want %d", replayed[0].Id, deliveries[1].Id)
+	}
+
+	// the ids keep increasing after a restart
+	enqueueDeliveries(t, queue, "new")
+	if delivery := dequeueDeliveries(t, queue, 1)[0]; delivery.Id <= replayed[1].Id {
+		t.Errorf("got id %d for a new delivery, want more than %d", delivery.Id, replayed[1].Id)
+	}
+
+	deadLetters, err := queue.DeadLetters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deadLetters) != 1 || string(deadLetters[0].Delivery.Body) != "dead" || deadLetters[0].Reason != "status 400: Invalid JSON" {
+		t.Errorf("got dead letters %+v, want the dead delivery", deadLetters)
+	}
+}
+
+func TestFileWebhookQueueCompactsJournal(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "journal")
+	queue := openFileWebhookQueue(t, path)
+	enqueueDeliveries(t, queue, "in flight")
+	inFlight := dequeueDeliveries(t, queue, 1)[0]
+	for i := range 1500 {
+		enqueueDeliveries(t, queue, fmt.Sprintf("delivery %d", i))
+		if err := queue.Ack(ctx, dequeueDeliveries(t, queue, 1)[0]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	enqueueDeliveries(t, queue, "queued")
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if records := strings.Count(string(content), "\n"); records > 1100 {
+		t.Errorf("got %d records in the journal, want it compacted", records)
+	}
+
+	queue.Close()
+	queue = openFileWebhookQueue(t, path)
+	replayed := dequeueDeliveries(t, queue, 2)
+	if want := []string{"in flight", "queued"}; !slices.Equal(deliveryBodies(replayed), want) {
+		t.Errorf("got deliveries %v after the compaction, want %v", deliveryBodies(replayed), want)
+	}
+	if replayed[0].Id != inFlight.Id {
+		t.Errorf("got id %d for the in flight delivery, want %d", replayed[0].Id, inFlight.Id)
+	}
+}
+
+func TestWebhookQueueNack(t *testing.T) {
+	queues := map[string]func(t *testing.T) WebhookQueue{
+		"memory": func(t *testing.T) WebhookQueue { return NewBoundedMemoryWebhookQueue(2) },
+		"file": func(t *testing.T) WebhookQueue {
+			return openFileWebhookQueue(t, filepath.Join(t.TempDir(), "journal"))
+		},
+	}
+	for name, newQueue := range queues {
+		t.Run(name, func(t *testing.T) {
+			queue := newQueue(t)
+			enqueueDeliveries(t, queue, "first", "second")
+			first := dequeueDeliveries(t, queue, 1)[0]
+			enqueueDeliveries(t, queue, "third")
+			if err := queue.Nack(context.Background(), first); err != nil {
+				t.Fatal(err)
+			}
+			if got := queue.Len(); got != 3 {
+				t.Errorf("got %d queued deliveries, want 3", got)
+			}
+			deliveries := dequeueDeliveries(t, queue, 3)
+			if want := []string{"first", "second", "third"}; !slices.Equal(deliveryBodies(deliveries), want) {
+				t.Errorf("got deliveries %v, want %v", deliveryBodies(deliveries), want)
+			}
+		})
+	}
+}
+
+func TestFileWebhookQueueReplaysNackedDeliveries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	queue := openFileWebhookQueue(t, path)
+	enqueueDeliveries(t, queue, "nacked")
+	if err := queue.Nack(context.Background(), dequeueDeliveries(t, queue, 1)[0]); err != nil {
+		t.Fatal(err)
+	}
+	queue.Close()
+
+	queue = openFileWebhookQueue(t, path)
+	if got := deliveryBodies(dequeueDeliveries(t, queue, 1)); got[0] != "nacked" {
+		t.Errorf("got delivery %q after a restart, want the nacked one", got[0])
+	}
+}
+
+func TestBoundedMemoryWebhookQueueRejectsWhenFull(t *testing.T) {
+	ctx := context.Background()
+	queue := NewBoundedMemoryWebhookQueue(2)
+	enqueueDeliveries(t, queue, "first", "second")
+	if err := queue.Enqueue(ctx, WebhookDelivery{Body: []byte("third")}); !errors.Is(err, ErrWebhookQueueFull) {
+		t.Fatalf("Enqueue() on a full queue = %v, want %v", err, ErrWebhookQueueFull)
+	}
+	// the oldest deliveries are kept
+	if got := deliveryBodies(dequeueDeliveries(t, queue, 2)); !slices.Equal(got, []string{"first", "second"}) {
+		t.Errorf("got deliveries %v, want the first two", got)
+	}
+
+	for i := range 3 {
+		queue.DeadLetter(ctx, WebhookDeadLetter{Delivery: WebhookDelivery{Id: uint64(i)}})
+	}
+	deadLetters := queue.DeadLetters()
+	if len(deadLetters) != 2 || deadLetters[0].Delivery.Id != 1 {
+		t.Errorf("got %d dead letters starting with %d, want the last 2", len(deadLetters), deadLetters[0].Delivery.Id)
+	}
+}
+
+func TestWebhookQueueDequeueWaitsForContext(t *testing.T) {
+	queue := NewBoundedMemoryWebhookQueue(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := queue.Dequeue(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Dequeue() on an empty queue = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		queue.Enqueue(context.Background(), WebhookDelivery{Body: []byte("late")})
+	}()
+	if got := deliveryBodies(dequeueDeliveries(t, queue, 1)); got[0] != "late" {
+		t.Errorf("got delivery %q, want the late one", got[0])
+	}
+}
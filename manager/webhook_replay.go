@@ -0,0 +1,160 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gTahidi/wapi.go/pkg/events"
+)
+
+// WebhookReplayOptions selects the archived notifications to replay and the events to publish.
+type WebhookReplayOptions struct {
+	// From and To bound the time the notifications were received at, inclusively. A zero time is no bound.
+	From time.Time
+	To   time.Time
+	// PhoneNumberIds keeps the changes of the given business phone numbers only. The changes without phone number,
+	// like the business account updates, are dropped when it is set.
+	PhoneNumberIds []string
+	// EventTypes keeps the events of the given types only.
+	EventTypes []events.EventType
+	// DryRun counts the events which would be published, and passes them to OnEvent, without publishing them.
+	DryRun bool
+	// OnEvent is called with every event replayed.
+	OnEvent func(record ArchivedWebhook, eventType events.EventType, event events.BaseEvent)
+}
+
+// WebhookReplayResult sums up a replay.
+type WebhookReplayResult struct {
+	// Records is the number of archived notifications read, Replayed the number of those which produced
+	// at least one event, and Failed the number of those which could not be processed.
+	Records  int
+	Replayed int
+	Failed   int
+	// Events is the number of events replayed by type.
+	Events map[events.EventType]int
+}
+
+// Add sums the result of another replay into this one.
+func (result *WebhookReplayResult) Add(other WebhookReplayResult) {
+	result.Records += other.Records
+	result.Replayed += other.Replayed
+	result.Failed += other.Failed
+	if result.Events == nil {
+		result.Events = map[events.EventType]int{}
+	}
+	for eventType, count := range other.Events {
+		result.Events[eventType] += count
+	}
+}
+
+// webhookReplay filters and counts the events published while replaying a notification.
+type webhookReplay struct {
+	options WebhookReplayOptions
+	record  ArchivedWebhook
+	result  WebhookReplayResult
+}
+
+// accept reports whether the event must be published.
+func (replay *webhookReplay) accept(eventType events.EventType, event events.BaseEvent) bool {
+	if len(replay.options.EventTypes) > 0 && !slices.Contains(replay.options.EventTypes, eventType) {
+		return false
+	}
+	replay.result.Events[eventType]++
+	if replay.options.OnEvent != nil {
+		replay.options.OnEvent(replay.record, eventType, event)
+	}
+	return !replay.options.DryRun
+}
+
+// ReplayWebhook processes an archived notification again, publishing its events to the event manager of the
+// webhook. The signature is not verified and the notification is not deduplicated, the events are published
+// even if they were already. The handlers replying to the events send their replies again.
+func (wh *WebhookManager) ReplayWebhook(ctx context.Context, record ArchivedWebhook, options WebhookReplayOptions) (WebhookReplayResult, error) {
+	replay := &webhookReplay{
+		options: options,
+		record:  record,
+		result:  WebhookReplayResult{Records: 1, Events: map[events.EventType]int{}},
+	}
+	if (!options.From.IsZero() && record.ReceivedAt.Before(options.From)) || (!options.To.IsZero() && record.ReceivedAt.After(options.To)) {
+		return replay.result, nil
+	}
+
+	payload, response := wh.parseNotification([]byte(record.Body))
+	if response.status == http.StatusOK {
+		if len(options.PhoneNumberIds) > 0 {
+			payload = filterPayloadByPhoneNumber(payload, options.PhoneNumberIds)
+		}
+		// a copy of the webhook without dedup store, publishing through the replay
+		replayer := &WebhookManager{
+			EventManager: wh.EventManager,
+			Requester:    wh.Requester,
+			logger:       wh.logger,
+			replay:       replay,
+			tenants:      wh.tenants,
+		}
+		response = replayer.processNotification(&deliveryDeduplicator{ctx: ctx, wh: replayer}, payload)
+	}
+	if response.status != http.StatusOK {
+		replay.result.Failed++
+		return replay.result, fmt.Errorf("error replaying webhook received at %s: status %d: %s", record.ReceivedAt.Format(time.RFC3339), response.status, response.body)
+	}
+	for _, count := range replay.result.Events {
+		if count > 0 {
+			replay.result.Replayed++
+			break
+		}
+	}
+	return replay.result, nil
+}
+
+// ReplayWebhookArchive replays the notifications of a JSONL archive, see ReplayWebhook. The notifications
+// which fail are logged and counted, the replay stops only when the archive can not be read or ctx is done.
+func (wh *WebhookManager) ReplayWebhookArchive(ctx context.Context, reader io.Reader, options WebhookReplayOptions) (WebhookReplayResult, error) {
+	result := WebhookReplayResult{Events: map[events.EventType]int{}}
+	err := ReadWebhookArchive(reader, func(record ArchivedWebhook) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		recordResult, err := wh.ReplayWebhook(ctx, record, options)
+		result.Add(recordResult)
+		if err != nil {
+			wh.logger.Warn("error replaying webhook", "error", err)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// filterPayloadByPhoneNumber keeps the changes of the given business phone numbers only.
+func filterPayloadByPhoneNumber(payload WhatsappApiNotificationPayloadSchemaType, phoneNumberIds []string) WhatsappApiNotificationPayloadSchemaType {
+	entries := make([]Entry, 0, len(payload.Entry))
+	for _, entry := range payload.Entry {
+		entry.Changes = slices.DeleteFunc(slices.Clone(entry.Changes), func(change Change) bool {
+			return !slices.Contains(phoneNumberIds, changePhoneNumberId(change))
+		})
+		if len(entry.Changes) > 0 {
+			entries = append(entries, entry)
+		}
+	}
+	payload.Entry = entries
+	return payload
+}
+
+// changePhoneNumberId returns the id of the business phone number a change is about, from its metadata,
+// or an empty string for the changes without metadata.
+func changePhoneNumberId(change Change) string {
+	value, ok := change.Value.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	metadata, ok := value["metadata"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	phoneNumberId, _ := metadata["phone_number_id"].(string)
+	return phoneNumberId
+}
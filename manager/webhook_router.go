@@ -0,0 +1,209 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gTahidi/wapi.go/internal"
+	"github.com/gTahidi/wapi.go/internal/request_client"
+	"github.com/gTahidi/wapi.go/pkg/events"
+)
+
+// ErrWebhookTenantExists is returned when a tenant is added with an id, a business account or a phone number
+// already routed to another tenant.
+var ErrWebhookTenantExists = errors.New("webhook tenant already exists")
+
+// WebhookTenantConfig configures a tenant of the webhook.
+type WebhookTenantConfig struct {
+	Id string `validate:"required"`
+	// BusinessAccountIds and PhoneNumberIds are the WhatsApp business accounts and the business phone numbers
+	// whose notifications are routed to the tenant. The phone number takes precedence over the business account
+	// when both are routed to different tenants.
+	BusinessAccountIds []string
+	PhoneNumberIds     []string
+	// AccessToken or TokenProvider authenticates the requests made by the events of the tenant, like replies.
+	// TokenProvider takes precedence over AccessToken.
+	AccessToken   string
+	TokenProvider TokenProvider
+}
+
+// WebhookTenant is a customer served by a webhook shared with other customers. The events of its business
+// accounts and phone numbers are published to its own event manager only, and reply with its own access token.
+type WebhookTenant struct {
+	Id           string
+	EventManager *EventManager
+	Requester    request_client.RequestClient
+	config       WebhookTenantConfig
+}
+
+// On registers a handler for the events of the tenant of the given type.
+func (tenant *WebhookTenant) On(eventType events.EventType, handler func(events.BaseEvent)) events.EventType {
+	return tenant.EventManager.On(eventType, handler)
+}
+
+// webhookRouter dispatches the changes of the notifications to the tenants they belong to.
+type webhookRouter struct {
+	mutex               sync.RWMutex
+	tenants             map[string]*WebhookTenant
+	byBusinessAccountId map[string]*WebhookTenant
+	byPhoneNumberId     map[string]*WebhookTenant
+}
+
+func newWebhookRouter() *webhookRouter {
+	return &webhookRouter{
+		tenants:             map[string]*WebhookTenant{},
+		byBusinessAccountId: map[string]*WebhookTenant{},
+		byPhoneNumberId:     map[string]*WebhookTenant{},
+	}
+}
+
+func (router *webhookRouter) add(tenant *WebhookTenant) error {
+	router.mutex.Lock()
+	defer router.mutex.Unlock()
+	if _, ok := router.tenants[tenant.Id]; ok {
+		return fmt.Errorf("%w: %s", ErrWebhookTenantExists, tenant.Id)
+	}
+	for _, businessAccountId := range tenant.config.BusinessAccountIds {
+		if other, ok := router.byBusinessAccountId[businessAccountId]; ok {
+			return fmt.Errorf("%w: business account %s is routed to %s", ErrWebhookTenantExists, businessAccountId, other.Id)
+		}
+	}
+	for _, phoneNumberId := range tenant.config.PhoneNumberIds {
+		if other, ok := router.byPhoneNumberId[phoneNumberId]; ok {
+			return fmt.Errorf("%w: phone number %s is routed to %s", ErrWebhookTenantExists, phoneNumberId, other.Id)
+		}
+	}
+	router.tenants[tenant.Id] = tenant
+	for _, businessAccountId := range tenant.config.BusinessAccountIds {
+		router.byBusinessAccountId[businessAccountId] = tenant
+	}
+	for _, phoneNumberId := range tenant.config.PhoneNumberIds {
+		router.byPhoneNumberId[phoneNumberId] = tenant
+	}
+	return nil
+}
+
+func (router *webhookRouter) remove(id string) *WebhookTenant {
+	router.mutex.Lock()
+	defer router.mutex.Unlock()
+	tenant, ok := router.tenants[id]
+	if !ok {
+		return nil
+	}
+	delete(router.tenants, id)
+	for _, businessAccountId := range tenant.config.BusinessAccountIds {
+		delete(router.byBusinessAccountId, businessAccountId)
+	}
+	for _, phoneNumberId := range tenant.config.PhoneNumberIds {
+		delete(router.byPhoneNumberId, phoneNumberId)
+	}
+	return tenant
+}
+
+func (router *webhookRouter) get(id string) *WebhookTenant {
+	router.mutex.RLock()
+	defer router.mutex.RUnlock()
+	return router.tenants[id]
+}
+
+func (router *webhookRouter) list() []*WebhookTenant {
+	if router == nil {
+		return nil
+	}
+	router.mutex.RLock()
+	defer router.mutex.RUnlock()
+	tenants := make([]*WebhookTenant, 0, len(router.tenants))
+	for _, tenant := range router.tenants {
+		tenants = append(tenants, tenant)
+	}
+	return tenants
+}
+
+// route returns the tenant a change belongs to, by its business phone number first and its business account
+// then, or nil when it belongs to no tenant.
+func (router *webhookRouter) route(entry Entry, change Change) *WebhookTenant {
+	if router == nil {
+		return nil
+	}
+	router.mutex.RLock()
+	defer router.mutex.RUnlock()
+	if phoneNumberId := changePhoneNumberId(change); phoneNumberId != "" {
+		if tenant, ok := router.byPhoneNumberId[phoneNumberId]; ok {
+			return tenant
+		}
+	}
+	return router.byBusinessAccountId[entry.Id]
+}
+
+// AddTenant routes the notifications of the business accounts and the phone numbers of a tenant to a new
+// event manager, whose events reply with the access token of the tenant. The notifications which belong to
+// no tenant are still published to the event manager of the webhook, which acts as the fallback. Tenants
+// can be added and removed while the webhook is serving.
+func (wh *WebhookManager) AddTenant(config WebhookTenantConfig) (*WebhookTenant, error) {
+	if err := internal.GetValidator().Struct(config); err != nil {
+		return nil, fmt.Errorf("invalid webhook tenant config: %w", err)
+	}
+	provider := config.TokenProvider
+	if provider == nil {
+		if config.AccessToken == "" {
+			return nil, fmt.Errorf("invalid webhook tenant config: tenant %s has no access token", config.Id)
+		}
+		provider = StaticTokenProvider(config.AccessToken)
+	}
+	tenant := &WebhookTenant{
+		Id:           config.Id,
+		EventManager: NewEventManager(),
+		Requester:    *wh.Requester.CloneWithTokenProvider(provider),
+		config:       config,
+	}
+	if err := wh.tenants.add(tenant); err != nil {
+		return nil, err
+	}
+	wh.logger.Debug("webhook tenant added", "tenant", tenant.Id)
+	return tenant, nil
+}
+
+// RemoveTenant stops routing the notifications of a tenant, which are published to the event manager of the
+// webhook again. It returns the removed tenant, or nil when there is no tenant with this id.
+func (wh *WebhookManager) RemoveTenant(id string) *WebhookTenant {
+	tenant := wh.tenants.remove(id)
+	if tenant != nil {
+		wh.logger.Debug("webhook tenant removed", "tenant", id)
+	}
+	return tenant
+}
+
+// Tenant returns the tenant with the given id, or nil when there is none.
+func (wh *WebhookManager) Tenant(id string) *WebhookTenant {
+	return wh.tenants.get(id)
+}
+
+// changeWebhook returns the copy of the webhook publishing the events of a change, which counts the events
+// it drops. It targets the event manager and the requester of the tenant the change belongs to, if any.
+func (wh *WebhookManager) changeWebhook(entry Entry, change Change) *WebhookManager {
+	target := &WebhookManager{
+		EventManager: wh.EventManager,
+		Requester:    wh.Requester,
+		logger:       wh.logger,
+		replay:       wh.replay,
+		tenants:      wh.tenants,
+	}
+	if tenant := wh.tenants.route(entry, change); tenant != nil {
+		target.EventManager = tenant.EventManager
+		target.Requester = tenant.Requester
+		target.logger = wh.logger.With("tenant", tenant.Id)
+	}
+	return target
+}
+
+// drainTenants waits until the handlers of every tenant are done with the events published so far.
+func (wh *WebhookManager) drainTenants(ctx context.Context) error {
+	for _, tenant := range wh.tenants.list() {
+		if err := tenant.EventManager.Drain(ctx); err != nil {
+			return fmt.Errorf("tenant %s: %w", tenant.Id, err)
+		}
+	}
+	return nil
+}
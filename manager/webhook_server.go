@@ -0,0 +1,219 @@
+package manager
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gTahidi/wapi.go/pkg/events"
+)
+
+const (
+	// DefaultWebhookServerPort is the port of the built-in webhook server when none is configured.
+	DefaultWebhookServerPort = 8080
+	// WebhookHealthPath answers 200 as long as the built-in webhook server is up.
+	WebhookHealthPath = "/healthz"
+	// WebhookReadinessPath answers 200 when the built-in webhook server accepts notifications, and 503 while it shuts down.
+	WebhookReadinessPath = "/readyz"
+	// webhookShutdownTimeout bounds the graceful shutdown triggered by the context passed to Start.
+	webhookShutdownTimeout = 10 * time.Second
+)
+
+// ErrWebhookServerStarted is returned when Start is called on a webhook server which is already running.
+var ErrWebhookServerStarted = errors.New("webhook server already started")
+
+// webhookServer is the state of the built-in webhook server.
+type webhookServer struct {
+	httpServer *http.Server
+	listener   net.Listener
+	ready      atomic.Bool
+	// done is closed when the server stopped serving, err is the reason why it stopped, if it was not shut down.
+	done chan struct{}
+	err  error
+	// stopped is closed when a shutdown completed, after the event handlers were drained.
+	shutdown    atomic.Bool
+	stopped     chan struct{}
+	stoppedOnce sync.Once
+}
+
+// Addr returns the address the built-in webhook server listens on, or nil if it is not started.
+func (wh *WebhookManager) Addr() net.Addr {
+	wh.serverMutex.Lock()
+	defer wh.serverMutex.Unlock()
+	if wh.server == nil {
+		return nil
+	}
+	return wh.server.listener.Addr()
+}
+
+// serverMux routes the requests of the built-in server to the webhook and to the health endpoints.
+func (wh *WebhookManager) serverMux(server *webhookServer) *http.ServeMux {
+	path := wh.path
+	if path == "" {
+		path = "/"
+	}
+	mux := http.NewServeMux()
+	mux.Handle(path, wh)
+	mux.HandleFunc(WebhookHealthPath, func(w http.ResponseWriter, r *http.Request) {
+		writeWebhookResponse(w, webhookResponse{http.StatusOK, "ok"})
+	})
+	mux.HandleFunc(WebhookReadinessPath, func(w http.ResponseWriter, r *http.Request) {
+		if !server.ready.Load() {
+			writeWebhookResponse(w, webhookResponse{http.StatusServiceUnavailable, "not ready"})
+			return
+		}
+		writeWebhookResponse(w, webhookResponse{http.StatusOK, "ready"})
+	})
+	return mux
+}
+
+// Start starts the built-in webhook server on the configured host and port, serving TLS when a certificate
+// is configured. It returns once the server listens, or with the error preventing it to, like an address
+// already in use. The server is shut down gracefully when ctx is done, or by calling Shutdown.
+// No signal handling is installed, stopping the server on SIGINT or SIGTERM is up to the application.
+func (wh *WebhookManager) Start(ctx context.Context) error {
+	wh.serverMutex.Lock()
+	defer wh.serverMutex.Unlock()
+	if wh.server != nil {
+		select {
+		case <-wh.server.done:
+			// the previous server stopped, a new one can be started
+		default:
+			return ErrWebhookServerStarted
+		}
+	}
+
+	port := wh.port
+	if port == 0 {
+		port = DefaultWebhookServerPort
+	}
+	address := net.JoinHostPort(wh.host, strconv.Itoa(port))
+
+	tlsConfig := wh.tlsConfig
+	if wh.tlsCertFile != "" || wh.tlsKeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(wh.tlsCertFile, wh.tlsKeyFile)
+		if err != nil {
+			return fmt.Errorf("error loading webhook server certificate: %w", err)
+		}
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		} else {
+			tlsConfig = tlsConfig.Clone()
+		}
+		tlsConfig.Certificates = append(tlsConfig.Certificates, certificate)
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("error starting webhook server: %w", err)
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	server := &webhookServer{listener: listener, done: make(chan struct{}), stopped: make(chan struct{})}
+	server.httpServer = &http.Server{
+		Handler:           wh.serverMux(server),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	server.ready.Store(true)
+	wh.server = server
+
+	go func() {
+		defer close(server.done)
+		if err := server.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			server.ready.Store(false)
+			server.err = err
+			wh.logger.Error("webhook server stopped", "error", err)
+		}
+	}()
+	go func() {
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), webhookShutdownTimeout)
+			defer cancel()
+			if err := wh.Shutdown(shutdownCtx); err != nil {
+				wh.logger.Error("error shutting down webhook server", "error", err)
+			}
+		case <-server.done:
+		}
+	}()
+
+	if wh.fastAck != nil {
+		// the workers are stopped by a shutdown, and started again with the server
+		wh.fastAck.start()
+	}
+	wh.logger.Info("listening to events", "address", listener.Addr().String(), "path", wh.path, "tls", tlsConfig != nil)
+	wh.publish(events.ReadyEventType, events.NewReadyEvent())
+	return nil
+}
+
+// Shutdown gracefully stops the built-in webhook server: it stops accepting connections, waits for the
+// notifications being received, then for the event handlers to be done with the events already published.
+// In fast-ack mode, the queued notifications are processed before the workers are stopped, Shutdown stops them
+// and drains the event handlers even when the built-in server is not used. It returns the context error if ctx
+// is done first.
+func (wh *WebhookManager) Shutdown(ctx context.Context) error {
+	wh.serverMutex.Lock()
+	server := wh.server
+	wh.serverMutex.Unlock()
+	if server == nil && wh.fastAck == nil {
+		return nil
+	}
+
+	if server != nil {
+		server.ready.Store(false)
+		server.shutdown.Store(true)
+		defer server.stoppedOnce.Do(func() { close(server.stopped) })
+		if err := server.httpServer.Shutdown(ctx); err != nil {
+			return fmt.Errorf("error shutting down webhook server: %w", err)
+		}
+	}
+	if wh.fastAck != nil {
+		if err := wh.fastAck.stop(ctx); err != nil {
+			return fmt.Errorf("error stopping webhook workers: %w", err)
+		}
+	}
+	if err := wh.EventManager.Drain(ctx); err != nil {
+		return fmt.Errorf("error draining event handlers: %w", err)
+	}
+	if err := wh.drainTenants(ctx); err != nil {
+		return fmt.Errorf("error draining event handlers: %w", err)
+	}
+	return nil
+}
+
+// Wait blocks until the built-in webhook server stops, including the draining of the event handlers when it
+// is shut down, and returns the error which stopped it, or nil if it was shut down. It returns straight away
+// when the server is not started.
+func (wh *WebhookManager) Wait() error {
+	wh.serverMutex.Lock()
+	server := wh.server
+	wh.serverMutex.Unlock()
+	if server == nil {
+		return nil
+	}
+	<-server.done
+	if server.shutdown.Load() {
+		<-server.stopped
+	}
+	return server.err
+}
+
+// ListenToEvents starts the built-in webhook server and blocks until it stops, see Start and Wait. No signal
+// handling is installed, so it blocks until another goroutine calls Shutdown, or until the server fails: use
+// Start with a context cancelled on SIGINT or SIGTERM, then Wait, for the server to stop with the application.
+func (wh *WebhookManager) ListenToEvents() error {
+	if err := wh.Start(context.Background()); err != nil {
+		wh.logger.Error("error starting webhook server", "error", err)
+		return err
+	}
+	return wh.Wait()
+}
@@ -0,0 +1,48 @@
+package manager
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// WebhookSignatureHeader is the header carrying the signature Meta computes over the body of every webhook request.
+const WebhookSignatureHeader = "X-Hub-Signature-256"
+
+var (
+	// ErrWebhookSignatureMissing is returned when a webhook request carries no signature.
+	ErrWebhookSignatureMissing = errors.New("webhook request is not signed")
+	// ErrWebhookSignatureInvalid is returned when the signature of a webhook request does not match its body.
+	ErrWebhookSignatureInvalid = errors.New("webhook request signature is invalid")
+)
+
+// VerifyWebhookSignature checks the X-Hub-Signature-256 header of a webhook request against its raw body,
+// which must be read before being parsed. It is meant for the users serving the webhook with their own server:
+//
+//	body, _ := io.ReadAll(r.Body)
+//	if err := manager.VerifyWebhookSignature(appSecret, body, r.Header.Get(manager.WebhookSignatureHeader)); err != nil {
+//		w.WriteHeader(http.StatusUnauthorized)
+//		return
+//	}
+func VerifyWebhookSignature(appSecret string, body []byte, signatureHeader string) error {
+	if signatureHeader == "" {
+		return ErrWebhookSignatureMissing
+	}
+	signature, found := strings.CutPrefix(signatureHeader, "sha256=")
+	if !found {
+		return ErrWebhookSignatureInvalid
+	}
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return ErrWebhookSignatureInvalid
+	}
+
+	mac := hmac.New(sha256.New, []byte(appSecret))
+	mac.Write(body)
+	if !hmac.Equal(mac.Sum(nil), expected) {
+		return ErrWebhookSignatureInvalid
+	}
+	return nil
+}
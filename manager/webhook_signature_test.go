@@ -0,0 +1,73 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gTahidi/wapi.go/internal/request_client"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	body := []byte(`{"object":"whatsapp_business_account"}`)
+	// HMAC-SHA256 of body with the key "app-secret"
+	signature := "sha256=0bf7374433906636bed1653bc7c64affb42fa5da9a39e251ab95c2e3a9c06923"
+
+	tests := []struct {
+		name      string
+		appSecret string
+		body      []byte
+		header    string
+		want      error
+	}{
+		{"valid signature", "app-secret", body, signature, nil},
+		{"uppercase hex", "app-secret", body, "sha256=" + strings.ToUpper(strings.TrimPrefix(signature, "sha256=")), nil},
+		{"missing header", "app-secret", body, "", ErrWebhookSignatureMissing},
+		{"wrong secret", "other-secret", body, signature, ErrWebhookSignatureInvalid},
+		{"tampered body", "app-secret", []byte(`{"object":"page"}`), signature, ErrWebhookSignatureInvalid},
+		{"sha1 signature", "app-secret", body, "sha1=0bf7374433906636bed1653bc7c64affb42fa5da", ErrWebhookSignatureInvalid},
+		{"missing prefix", "app-secret", body, strings.TrimPrefix(signature, "sha256="), ErrWebhookSignatureInvalid},
+		{"not hex", "app-secret", body, "sha256=not-hex", ErrWebhookSignatureInvalid},
+		{"truncated signature", "app-secret", body, signature[:20], ErrWebhookSignatureInvalid},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := VerifyWebhookSignature(test.appSecret, test.body, test.header); !errors.Is(err, test.want) {
+				t.Errorf("VerifyWebhookSignature() = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestWebhookRejectsUnsignedNotifications(t *testing.T) {
+	body := []byte(`{"object":"whatsapp_business_account"}`)
+	wh := NewWebhook(&WebhookManagerConfig{
+		Secret:       "secret",
+		AppSecret:    "app-secret",
+		EventManager: NewEventManager(),
+		Requester:    *request_client.NewRequestClient("token"),
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"signed", "sha256=0bf7374433906636bed1653bc7c64affb42fa5da9a39e251ab95c2e3a9c06923", http.StatusOK},
+		{"unsigned", "", http.StatusUnauthorized},
+		{"badly signed", "sha256=0bf7374433906636bed1653bc7c64affb42fa5da9a39e251ab95c2e3a9c06924", http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			header := http.Header{}
+			if test.header != "" {
+				header.Set(WebhookSignatureHeader, test.header)
+			}
+			if got := wh.receiveNotification(context.Background(), body, header); got.status != test.want {
+				t.Errorf("got status %d (%s), want %d", got.status, got.body, test.want)
+			}
+		})
+	}
+}
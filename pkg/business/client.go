@@ -1,8 +1,10 @@
 package business
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
 
@@ -20,6 +22,7 @@ type BusinessClient struct {
 	Template          *manager.TemplateManager
 	requester         *request_client.RequestClient
 	Catalog           *manager.CatalogManager
+	logger            *slog.Logger
 }
 
 // BusinessClientConfig holds the configuration for BusinessClient.
@@ -27,21 +30,25 @@ type BusinessClientConfig struct {
 	BusinessAccountId string `json:"businessAccountId" validate:"required"`
 	AccessToken       string `json:"accessToken" validate:"required"`
 	Requester         *request_client.RequestClient
+	// Logger is used to report failed requests, the logger of the requester is used when it is nil.
+	Logger *slog.Logger
 }
 
 // NewBusinessClient creates a new instance of BusinessClient.
 func NewBusinessClient(config *BusinessClientConfig) *BusinessClient {
+	logger := config.Logger
+	if logger == nil {
+		logger = config.Requester.Logger()
+	}
 	return &BusinessClient{
 		BusinessAccountId: config.BusinessAccountId,
 		AccessToken:       config.AccessToken,
 		PhoneNumber: manager.NewPhoneNumberManager(&manager.PhoneNumberManagerConfig{
 			BusinessAccountId: config.BusinessAccountId,
-			ApiAccessToken:    config.AccessToken,
 			Requester:         config.Requester,
 		}),
 		Template: manager.NewTemplateManager(&manager.TemplateManagerConfig{
 			BusinessAccountId: config.BusinessAccountId,
-			ApiAccessToken:    config.AccessToken,
 			Requester:         config.Requester,
 		}),
 		Catalog: manager.NewCatalogManager(&manager.CatalogManagerConfig{
@@ -49,9 +56,21 @@ func NewBusinessClient(config *BusinessClientConfig) *BusinessClient {
 			Requester:         config.Requester,
 		}),
 		requester: config.Requester,
+		logger:    logger,
 	}
 }
 
+// SetAccessToken swaps the access token the business client and its managers send their requests with.
+func (bc *BusinessClient) SetAccessToken(token string) {
+	bc.AccessToken = token
+	bc.requester.SetAccessToken(token)
+}
+
+// SetTokenProvider swaps the provider of the access tokens the business client and its managers send their requests with.
+func (bc *BusinessClient) SetTokenProvider(provider manager.TokenProvider) {
+	bc.requester.SetTokenProvider(provider)
+}
+
 // GetBusinessId returns the business account ID.
 func (bc *BusinessClient) GetBusinessId() string {
 	return bc.BusinessAccountId
@@ -89,10 +108,15 @@ type FetchBusinessAccountResponse struct {
 
 // This method fetches the business account details.
 func (client *BusinessClient) Fetch() (*FetchBusinessAccountResponse, error) {
+	return client.FetchContext(context.Background())
+}
+
+// FetchContext is like Fetch but uses ctx for the requests made to the Graph API.
+func (client *BusinessClient) FetchContext(ctx context.Context) (*FetchBusinessAccountResponse, error) {
 	apiRequest := client.requester.NewApiRequest(client.BusinessAccountId, http.MethodGet)
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
-		fmt.Println("Error while fetching business account", err)
+		client.logger.DebugContext(ctx, "error while fetching business account", "business_account_id", client.BusinessAccountId, "error", err)
 		return nil, err
 	}
 	var responseToReturn FetchBusinessAccountResponse
@@ -142,6 +166,11 @@ type WhatsappBusinessAccountAnalyticsResponse struct {
 
 // FetchAnalytics fetches the analytics for the business account.
 func (client *BusinessClient) FetchAnalytics(options AccountAnalyticsOptions) (WhatsappBusinessAccountAnalyticsResponse, error) {
+	return client.FetchAnalyticsContext(context.Background(), options)
+}
+
+// FetchAnalyticsContext is like FetchAnalytics but uses ctx for the requests made to the Graph API.
+func (client *BusinessClient) FetchAnalyticsContext(ctx context.Context, options AccountAnalyticsOptions) (WhatsappBusinessAccountAnalyticsResponse, error) {
 	apiRequest := client.requester.NewApiRequest(client.BusinessAccountId, http.MethodGet)
 	analyticsField := apiRequest.AddField(request_client.ApiRequestQueryParamField{
 		Name:    "analytics",
@@ -174,10 +203,9 @@ func (client *BusinessClient) FetchAnalytics(options AccountAnalyticsOptions) (W
 		// get all country codes
 		analyticsField.AddFilter("country_codes", "[]")
 	}
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
-		// return wapi.go custom error here
-		fmt.Println("Error while fetching business account", err)
+		client.logger.DebugContext(ctx, "error while fetching business account analytics", "business_account_id", client.BusinessAccountId, "error", err)
 		return WhatsappBusinessAccountAnalyticsResponse{}, err
 	}
 	var responseWrapper struct {
@@ -285,6 +313,11 @@ type WhatsAppConversationAnalyticsResponse struct {
 
 // ConversationAnalytics fetches the conversation analytics for the business account.
 func (client *BusinessClient) ConversationAnalytics(options ConversationAnalyticsOptions) (*WhatsAppConversationAnalyticsResponse, error) {
+	return client.ConversationAnalyticsContext(context.Background(), options)
+}
+
+// ConversationAnalyticsContext is like ConversationAnalytics but uses ctx for the requests made to the Graph API.
+func (client *BusinessClient) ConversationAnalyticsContext(ctx context.Context, options ConversationAnalyticsOptions) (*WhatsAppConversationAnalyticsResponse, error) {
 	apiRequest := client.requester.NewApiRequest(client.BusinessAccountId, http.MethodGet)
 	analyticsField := apiRequest.AddField(request_client.ApiRequestQueryParamField{
 		Name:    "conversation_analytics",
@@ -366,10 +399,9 @@ func (client *BusinessClient) ConversationAnalytics(options ConversationAnalytic
 		analyticsField.AddFilter("dimensions", "[]")
 	}
 
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
-		// return wapi.go custom error here
-		fmt.Println("Error while fetching conversation analytics", err)
+		client.logger.DebugContext(ctx, "error while fetching conversation analytics", "business_account_id", client.BusinessAccountId, "error", err)
 		return nil, err
 	}
 
@@ -462,6 +494,11 @@ type TemplateAnalyticsResponse struct {
 
 // TemplateAnalytics fetches the template analytics for the business account.
 func (client *BusinessClient) TemplateAnalytics(options TemplateAnalyticsOptions) (*TemplateAnalyticsResponse, error) {
+	return client.TemplateAnalyticsContext(context.Background(), options)
+}
+
+// TemplateAnalyticsContext is like TemplateAnalytics but uses ctx for the requests made to the Graph API.
+func (client *BusinessClient) TemplateAnalyticsContext(ctx context.Context, options TemplateAnalyticsOptions) (*TemplateAnalyticsResponse, error) {
 	apiRequest := client.requester.NewApiRequest(client.BusinessAccountId, http.MethodGet)
 	analyticsField := apiRequest.AddField(request_client.ApiRequestQueryParamField{
 		Name:    "template_analytics",
@@ -495,9 +532,9 @@ func (client *BusinessClient) TemplateAnalytics(options TemplateAnalyticsOptions
 		analyticsField.AddFilter("after", options.After)
 	}
 
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
-		fmt.Println("Error while fetching template analytics", err)
+		client.logger.DebugContext(ctx, "error while fetching template analytics", "business_account_id", client.BusinessAccountId, "error", err)
 		return nil, err
 	}
 
@@ -1,6 +1,11 @@
 package wapi
 
 import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
 	"github.com/gTahidi/wapi.go/internal/request_client"
 	"github.com/gTahidi/wapi.go/manager"
 	"github.com/gTahidi/wapi.go/pkg/business"
@@ -14,9 +19,82 @@ type ClientConfig struct {
 	ApiAccessToken    string
 	WebhookSecret     string `validate:"required"`
 
-	// these two are not required, because may be user want to use their own server
-	WebhookPath       string
-	WebhookServerPort int
+	// TokenProvider provides the access token of every request, for tokens which rotate. It takes precedence over ApiAccessToken.
+	TokenProvider manager.TokenProvider
+	// AppSecret is the secret of the Meta app. The webhook rejects the notifications which are not signed with it,
+	// and requests are signed with an appsecret_proof derived from it when AppSecretProof is set.
+	AppSecret      string
+	AppSecretProof bool
+
+	// these are not required, because may be user want to use their own server
+	WebhookPath        string
+	WebhookServerHost  string // WebhookServerHost is the host the built-in server listens on, all the interfaces by default
+	WebhookServerPort  int    // WebhookServerPort is the port the built-in server listens on, manager.DefaultWebhookServerPort by default
+	WebhookTLSCertFile string // WebhookTLSCertFile and WebhookTLSKeyFile make the built-in server serve TLS
+	WebhookTLSKeyFile  string
+	// WebhookDedupStore enables the deduplication of the notifications redelivered by Meta, see manager.DedupStore
+	WebhookDedupStore manager.DedupStore
+	// WebhookFastAck makes the webhook acknowledge the notifications once queued, see manager.FastAckConfig
+	WebhookFastAck *manager.FastAckConfig
+	// WebhookArchive archives the raw notifications for replay, see manager.FileWebhookArchive
+	WebhookArchive manager.WebhookArchiver
+	// WebhookMaxBodyBytes is the size limit of the notifications, manager.DefaultWebhookMaxBodyBytes by default
+	WebhookMaxBodyBytes int64
+
+	// these configure how the SDK talks to the Graph API, all of them are optional
+	HttpClient      *http.Client          // HttpClient is reused for every request, defaults to a new http.Client
+	Transport       http.RoundTripper     // Transport overrides the transport of HttpClient
+	BaseUrl         string                // BaseUrl is the Graph API host, e.g. "graph.facebook.com" or "http://127.0.0.1:8081" for a local mock server
+	RequestProtocol string                // RequestProtocol is the scheme of the requests, "https" by default
+	ApiVersion      string                // ApiVersion is the Graph API version, e.g. "v24.0"
+	RequestTimeout  time.Duration         // RequestTimeout is the default timeout of a single request, zero means no timeout
+	RetryPolicy     *manager.RetryPolicy  // RetryPolicy controls how failed requests are retried, defaults to manager.DefaultRetryPolicy()
+	Interceptors    []manager.Interceptor // Interceptors wrap every request sent to the Graph API, the first one being the outermost
+
+	// Logger receives the logs of the SDK, which is silent when it is nil
+	Logger *slog.Logger
+
+	// ThroughputLimiter paces the messages sent by the messaging clients, messages are not paced when nil
+	ThroughputLimiter *manager.ThroughputLimiter
+}
+
+// requestClientOptions maps the http related configuration to request client options.
+func (config *ClientConfig) requestClientOptions() []request_client.RequestClientOption {
+	options := []request_client.RequestClientOption{}
+	if config.HttpClient != nil {
+		options = append(options, request_client.WithHttpClient(config.HttpClient))
+	}
+	if config.Transport != nil {
+		options = append(options, request_client.WithTransport(config.Transport))
+	}
+	if config.BaseUrl != "" {
+		options = append(options, request_client.WithBaseUrl(config.BaseUrl))
+	}
+	if config.RequestProtocol != "" {
+		options = append(options, request_client.WithRequestProtocol(config.RequestProtocol))
+	}
+	if config.ApiVersion != "" {
+		options = append(options, request_client.WithApiVersion(config.ApiVersion))
+	}
+	if config.RequestTimeout > 0 {
+		options = append(options, request_client.WithTimeout(config.RequestTimeout))
+	}
+	if config.RetryPolicy != nil {
+		options = append(options, request_client.WithRetryPolicy(*config.RetryPolicy))
+	}
+	if len(config.Interceptors) > 0 {
+		options = append(options, request_client.WithInterceptors(config.Interceptors...))
+	}
+	if config.Logger != nil {
+		options = append(options, request_client.WithLogger(config.Logger))
+	}
+	if config.TokenProvider != nil {
+		options = append(options, request_client.WithTokenProvider(config.TokenProvider))
+	}
+	if config.AppSecretProof && config.AppSecret != "" {
+		options = append(options, request_client.WithAppSecretProof(config.AppSecret))
+	}
+	return options
 }
 
 type Client struct {
@@ -25,38 +103,55 @@ type Client struct {
 	eventManager *manager.EventManager       // eventManager is the event manager.
 	webhook      *manager.WebhookManager     // webhook is the webhook manager.
 	requester    *request_client.RequestClient
+	// throughputLimiter is shared by the message managers of every messaging client.
+	throughputLimiter *manager.ThroughputLimiter
 
-	apiAccessToken    string
 	businessAccountId string
 }
 
 func New(config *ClientConfig) *Client {
-	eventManager := *manager.NewEventManager()
-	requester := *request_client.NewRequestClient(config.ApiAccessToken)
+	eventManager := manager.NewEventManager()
+	requester := request_client.NewRequestClient(config.ApiAccessToken, config.requestClientOptions()...)
 	return &Client{
 		businessAccountId: config.BusinessAccountId,
-		apiAccessToken:    config.BusinessAccountId,
 		Messaging:         []messaging.MessagingClient{},
-		eventManager:      &eventManager,
+		eventManager:      eventManager,
 		Business: *business.NewBusinessClient(&business.BusinessClientConfig{
 			BusinessAccountId: config.BusinessAccountId,
 			AccessToken:       config.ApiAccessToken,
-			Requester:         &requester,
+			Requester:         requester,
+		}),
+		webhook: manager.NewWebhook(&manager.WebhookManagerConfig{
+			Path:         config.WebhookPath,
+			Secret:       config.WebhookSecret,
+			Host:         config.WebhookServerHost,
+			Port:         config.WebhookServerPort,
+			TLSCertFile:  config.WebhookTLSCertFile,
+			TLSKeyFile:   config.WebhookTLSKeyFile,
+			AppSecret:    config.AppSecret,
+			DedupStore:   config.WebhookDedupStore,
+			FastAck:      config.WebhookFastAck,
+			Archive:      config.WebhookArchive,
+			MaxBodyBytes: config.WebhookMaxBodyBytes,
+			EventManager: eventManager,
+			Requester:    *requester,
 		}),
-		webhook:   manager.NewWebhook(&manager.WebhookManagerConfig{Path: config.WebhookPath, Secret: config.WebhookSecret, Port: config.WebhookServerPort, EventManager: eventManager, Requester: requester}),
-		requester: &requester,
+		requester:         requester,
+		throughputLimiter: config.ThroughputLimiter,
 	}
 }
 
 func (client *Client) NewMessagingClient(phoneNumberId string) *messaging.MessagingClient {
 	// Create a new request client
 
+	messageManager := manager.NewMessageManager(*client.requester, phoneNumberId)
+	messageManager.SetThroughputLimiter(client.throughputLimiter)
+
 	// Create a new Client instance with the provided configurations
 	messagingClient := &messaging.MessagingClient{
 		Media:             *manager.NewMediaManager(*client.requester),
-		Message:           *manager.NewMessageManager(*client.requester, phoneNumberId),
+		Message:           *messageManager,
 		PhoneNumberId:     phoneNumberId,
-		ApiAccessToken:    client.apiAccessToken,
 		BusinessAccountId: client.businessAccountId,
 		Requester:         client.requester,
 	}
@@ -65,12 +160,28 @@ func (client *Client) NewMessagingClient(phoneNumberId string) *messaging.Messag
 	return messagingClient
 }
 
-// GetWebhookGetRequestHandler returns the handler function for handling GET requests to the webhook.
+// WebhookHandler returns a standard http.Handler serving both the verification (GET) and the notification (POST)
+// requests of the webhook, which can be mounted on net/http, chi or any router accepting an http.Handler.
+func (client *Client) WebhookHandler() http.Handler {
+	return client.webhook
+}
+
+// GetWebhookVerificationHandler returns the net/http handler function for handling GET requests to the webhook.
+func (client *Client) GetWebhookVerificationHandler() http.HandlerFunc {
+	return client.webhook.HandleVerification
+}
+
+// GetWebhookNotificationHandler returns the net/http handler function for handling POST requests to the webhook.
+func (client *Client) GetWebhookNotificationHandler() http.HandlerFunc {
+	return client.webhook.HandleNotification
+}
+
+// GetWebhookGetRequestHandler returns the echo handler function for handling GET requests to the webhook.
 func (client *Client) GetWebhookGetRequestHandler() func(c echo.Context) error {
 	return client.webhook.GetRequestHandler
 }
 
-// GetWebhookPostRequestHandler returns the handler function for handling POST requests to the webhook.
+// GetWebhookPostRequestHandler returns the echo handler function for handling POST requests to the webhook.
 func (client *Client) GetWebhookPostRequestHandler() func(c echo.Context) error {
 	return client.webhook.PostRequestHandler
 }
@@ -81,11 +192,71 @@ func (client *Client) On(eventType events.EventType, handler func(events.BaseEve
 		EventManager.On(eventType, handler)
 }
 
-// InitiateClient initializes the client and starts listening to events from the webhook.
-// It returns true if the client was successfully initiated.
+// AddTenant routes the events of the business accounts and the phone numbers of a tenant to handlers of its own,
+// registered with the On method of the returned tenant, which reply with the access token of the tenant. The events
+// which belong to no tenant are published to the handlers registered with client.On.
+func (client *Client) AddTenant(config manager.WebhookTenantConfig) (*manager.WebhookTenant, error) {
+	return client.webhook.AddTenant(config)
+}
+
+// RemoveTenant stops routing the events of a tenant to its handlers.
+func (client *Client) RemoveTenant(id string) *manager.WebhookTenant {
+	return client.webhook.RemoveTenant(id)
+}
+
+// Initiate starts listening to events from the webhook and blocks until the webhook server stops. No signal
+// handling is installed, so it blocks until another goroutine calls Shutdown, and only then reports with false
+// an error of the server, or straight away when it could not start. Use Start, with a context cancelled on
+// SIGINT or SIGTERM, then Wait, for the server to stop with the application.
 func (client *Client) Initiate() bool {
-	client.webhook.ListenToEvents()
-	return true
+	return client.webhook.ListenToEvents() == nil
+}
+
+// Start starts the built-in webhook server and returns once it listens, or with the error preventing it to.
+// The server is shut down gracefully when ctx is done, the SDK does not handle any signal itself:
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+//	defer stop()
+//	if err := client.Start(ctx); err != nil {
+//		log.Fatal(err)
+//	}
+//	client.Wait()
+func (client *Client) Start(ctx context.Context) error {
+	return client.webhook.Start(ctx)
+}
+
+// Shutdown gracefully stops the built-in webhook server, waiting for the event handlers to be done with the events already received.
+func (client *Client) Shutdown(ctx context.Context) error {
+	return client.webhook.Shutdown(ctx)
+}
+
+// Wait blocks until the built-in webhook server stops, and returns the error which stopped it, if any.
+func (client *Client) Wait() error {
+	return client.webhook.Wait()
+}
+
+// NewBatch creates an empty batch, which sends its operations with the access token of the client, see manager.Batch.
+func (client *Client) NewBatch() *manager.Batch {
+	return client.requester.NewBatch()
+}
+
+// NewApiRequest creates a request to the given path of the Graph API, like "<CATALOG_ID>/products", which is sent
+// with the access token of the client when executed or added to a batch.
+func (client *Client) NewApiRequest(path, method string) *manager.ApiRequest {
+	return client.requester.NewApiRequest(path, method)
+}
+
+// SetAccessToken swaps the access token of every request made by the client, its managers and its messaging clients,
+// but for the ones given a token of their own, see manager.TokenProvider.
+func (client *Client) SetAccessToken(token string) {
+	client.Business.AccessToken = token
+	client.requester.SetAccessToken(token)
+}
+
+// SetTokenProvider swaps the provider of the access tokens of every request made by the client, its managers and its messaging
+// clients, but for the ones given a token of their own, see manager.TokenProvider.
+func (client *Client) SetTokenProvider(provider manager.TokenProvider) {
+	client.requester.SetTokenProvider(provider)
 }
 
 // GetMediaManager returns a MediaManager for uploading media (e.g., for template headers).
@@ -99,7 +270,6 @@ func (client *Client) GetMediaManager() *manager.MediaManager {
 func (client *Client) GetFlowManager() *manager.FlowManager {
 	return manager.NewFlowManager(&manager.FlowManagerConfig{
 		BusinessAccountId: client.businessAccountId,
-		ApiAccessToken:    client.apiAccessToken,
 		Requester:         client.requester,
 	})
 }
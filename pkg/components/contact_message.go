@@ -44,7 +44,7 @@ type ContactAddress struct {
 	State       string      `json:"state,omitempty"`
 	Zip         string      `json:"zip,omitempty"`
 	Country     string      `json:"country,omitempty"`
-	CountryCode string      `json:"countryCode,omitempty"`
+	CountryCode string      `json:"country_code,omitempty"`
 	Type        AddressType `json:"type" validate:"required"`
 }
 
@@ -343,8 +343,5 @@ func (m *TemplateMessage) ToJson(configs ApiCompatibleJsonConverterConfigs) ([]b
 		return nil, fmt.Errorf("error marshalling json: %v", err)
 	}
 
-	// Debug logging: print the actual JSON being sent
-	fmt.Printf("[DEBUG] Template Message JSON: %s\n", string(jsonToReturn))
-
 	return jsonToReturn, nil
 }
@@ -21,7 +21,7 @@ type AccountUpdateEvent struct {
 }
 
 type BanInfo struct {
-	WabaBanState string
+	WabaBanState []string
 	WabaBanDate  string
 }
 
@@ -1,6 +1,9 @@
 package events
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -46,6 +49,7 @@ type BaseMessageEvent struct {
 	Timestamp         string              `json:"timestamp"`
 	IsForwarded       bool                `json:"is_forwarded"`
 	PhoneNumber       BusinessPhoneNumber `json:"phone_number"`
+	Referral          *AdSource           `json:"referral,omitempty"` // * the ad the user clicked to send the message, if any
 }
 
 type BaseMessageEventParams struct {
@@ -58,6 +62,7 @@ type BaseMessageEventParams struct {
 	SenderName        string
 	IsForwarded       bool
 	Context           MessageContext // * this context will not be present if in case a message is a reply to another message
+	Referral          *AdSource      // * the ad the user clicked to send the message, nil if the message does not come from an ad
 	Requester         request_client.RequestClient
 }
 
@@ -73,6 +78,7 @@ func NewBaseMessageEvent(params BaseMessageEventParams) BaseMessageEvent {
 		BusinessAccountId: params.BusinessAccountId,
 		From:              params.From,
 		SenderUserId:      params.SenderUserId,
+		Referral:          params.Referral,
 	}
 }
 
@@ -80,8 +86,13 @@ func (bme BaseMessageEvent) GetEventType() string {
 	return "message"
 }
 
-// Reply to the message
+// Reply to the message, returning the id of the reply.
 func (baseMessageEvent *BaseMessageEvent) Reply(Message components.BaseMessage) (string, error) {
+	return baseMessageEvent.ReplyContext(context.Background(), Message)
+}
+
+// ReplyContext is like Reply but uses ctx for the requests made to the Graph API.
+func (baseMessageEvent *BaseMessageEvent) ReplyContext(ctx context.Context, Message components.BaseMessage) (string, error) {
 	body, err := Message.ToJson(components.ApiCompatibleJsonConverterConfigs{
 		SendToPhoneNumber: baseMessageEvent.From,
 		ReplyToMessageId:  baseMessageEvent.MessageId,
@@ -93,14 +104,35 @@ func (baseMessageEvent *BaseMessageEvent) Reply(Message components.BaseMessage)
 
 	apiRequest := baseMessageEvent.requester.NewApiRequest(strings.Join([]string{baseMessageEvent.PhoneNumber.Id, "messages"}, "/"), http.MethodPost)
 	apiRequest.SetBody(string(body))
-	apiRequest.Execute()
+	responseStr, err := apiRequest.Execute(ctx)
+	if err != nil {
+		return "", err
+	}
 
-	return "", nil
+	var sendResponse replyResponse
+	if err := json.Unmarshal([]byte(responseStr), &sendResponse); err != nil {
+		return "", fmt.Errorf("error unmarshalling response: %v", err)
+	}
+	if len(sendResponse.Messages) == 0 {
+		return "", fmt.Errorf("error sending reply: no message id in response")
+	}
+	return sendResponse.Messages[0].Id, nil
+}
 
+// replyResponse is the part of the response to a sent message holding its id.
+type replyResponse struct {
+	Messages []struct {
+		Id string `json:"id"`
+	} `json:"messages"`
 }
 
-// React to the message
+// React to the message, returning the id of the reaction.
 func (baseMessageEvent *BaseMessageEvent) React(emoji string) (string, error) {
+	return baseMessageEvent.ReactContext(context.Background(), emoji)
+}
+
+// ReactContext is like React but uses ctx for the requests made to the Graph API.
+func (baseMessageEvent *BaseMessageEvent) ReactContext(ctx context.Context, emoji string) (string, error) {
 	reactionMessage, err := components.NewReactionMessage(components.ReactionMessageParams{
 		Emoji:     emoji,
 		MessageId: baseMessageEvent.MessageId,
@@ -108,8 +140,7 @@ func (baseMessageEvent *BaseMessageEvent) React(emoji string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	baseMessageEvent.Reply(reactionMessage)
-	return "", nil
+	return baseMessageEvent.ReplyContext(ctx, reactionMessage)
 }
 
 // BaseMediaMessageEvent represents a base media message event which contains media information.
@@ -4,6 +4,8 @@ type BusinessCapabilityUpdateEvent struct {
 	BaseBusinessAccountEvent
 	MaxDailyConversationPerPhone int64
 	MaxPhoneNumbersPerBusiness   int64
+	// MaxDailyConversationsPerBusiness is the messaging limit shared by the phone numbers of the business, when set.
+	MaxDailyConversationsPerBusiness int64
 }
 
 func NewBusinessCapabilityUpdateEvent(baseEvent *BaseBusinessAccountEvent, maxDailyConversationPerPhone int64, maxPhoneNumbersPerBusiness int64) *BusinessCapabilityUpdateEvent {
@@ -0,0 +1,79 @@
+package events
+
+import "encoding/json"
+
+// WebhookError is an error reported by Meta in a notification.
+type WebhookError struct {
+	Code    int    `json:"code"`
+	Title   string `json:"title"`
+	Message string `json:"message,omitempty"`
+	Details string `json:"details,omitempty"`
+	Href    string `json:"href,omitempty"`
+}
+
+// WebhookIssue describes the errors reported by Meta in a notification, or the error raised by the webhook
+// while processing it.
+type WebhookIssue struct {
+	BusinessAccountId string              `json:"business_account_id"`
+	PhoneNumber       BusinessPhoneNumber `json:"phone_number"`
+	MessageId         string              `json:"message_id,omitempty"` // * empty when the issue is not about a message
+	Errors            []WebhookError      `json:"errors,omitempty"`
+	Err               error               `json:"-"`
+	ErrorMessage      string              `json:"error,omitempty"` // * message of Err
+	// Raw is the JSON of the message, or of the notification value, the issue is about.
+	Raw json.RawMessage `json:"raw,omitempty"`
+}
+
+// ErrorEvent is published when a notification reports errors, or when a message can not be processed.
+type ErrorEvent struct {
+	BaseSystemEvent `json:",inline"`
+	WebhookIssue    `json:",inline"`
+}
+
+// NewErrorEvent creates a new instance of ErrorEvent.
+func NewErrorEvent(baseSystemEvent BaseSystemEvent, issue WebhookIssue) *ErrorEvent {
+	if issue.Err != nil && issue.ErrorMessage == "" {
+		issue.ErrorMessage = issue.Err.Error()
+	}
+	return &ErrorEvent{
+		BaseSystemEvent: baseSystemEvent,
+		WebhookIssue:    issue,
+	}
+}
+
+// WarnEvent is published when a message is processed, but Meta reports errors about it.
+type WarnEvent struct {
+	BaseSystemEvent `json:",inline"`
+	WebhookIssue    `json:",inline"`
+}
+
+// NewWarnEvent creates a new instance of WarnEvent.
+func NewWarnEvent(baseSystemEvent BaseSystemEvent, issue WebhookIssue) *WarnEvent {
+	if issue.Err != nil && issue.ErrorMessage == "" {
+		issue.ErrorMessage = issue.Err.Error()
+	}
+	return &WarnEvent{
+		BaseSystemEvent: baseSystemEvent,
+		WebhookIssue:    issue,
+	}
+}
+
+// UnknownEvent is published for the messages the webhook does not support, like the messages Meta reports
+// as unsupported, so that they can be stored or alerted on instead of being lost.
+type UnknownEvent struct {
+	BaseMessageEvent `json:",inline"`
+	MessageType      string         `json:"message_type"`
+	Errors           []WebhookError `json:"errors,omitempty"`
+	// Raw is the JSON of the message.
+	Raw json.RawMessage `json:"raw"`
+}
+
+// NewUnknownEvent creates a new instance of UnknownEvent.
+func NewUnknownEvent(baseMessageEvent BaseMessageEvent, messageType string, errors []WebhookError, raw json.RawMessage) *UnknownEvent {
+	return &UnknownEvent{
+		BaseMessageEvent: baseMessageEvent,
+		MessageType:      messageType,
+		Errors:           errors,
+		Raw:              raw,
+	}
+}
@@ -0,0 +1,43 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// FlowCompletionEvent represents the reply sent when a user completes a flow.
+type FlowCompletionEvent struct {
+	BaseMessageEvent `json:",inline"`
+	FlowToken        string `json:"flow_token"`
+	Name             string `json:"name"`
+	Body             string `json:"body"`
+	// Response is the decoded response_json of the reply, holding the data submitted in the flow.
+	Response     map[string]interface{} `json:"response"`
+	ResponseJson string                 `json:"response_json"`
+}
+
+// NewFlowCompletionEvent creates a new FlowCompletionEvent instance, decoding the response_json of the reply.
+func NewFlowCompletionEvent(baseMessageEvent BaseMessageEvent, name, body, responseJson string) (*FlowCompletionEvent, error) {
+	event := &FlowCompletionEvent{
+		BaseMessageEvent: baseMessageEvent,
+		Name:             name,
+		Body:             body,
+		ResponseJson:     responseJson,
+	}
+	if err := event.DecodeResponse(&event.Response); err != nil {
+		return nil, err
+	}
+	if flowToken, ok := event.Response["flow_token"].(string); ok {
+		event.FlowToken = flowToken
+	}
+	return event, nil
+}
+
+// DecodeResponse unmarshals the response_json of the reply into v, a pointer to a struct matching the data
+// of the terminal screen of the flow.
+func (event *FlowCompletionEvent) DecodeResponse(v interface{}) error {
+	if err := json.Unmarshal([]byte(event.ResponseJson), v); err != nil {
+		return fmt.Errorf("error decoding flow response: %w", err)
+	}
+	return nil
+}
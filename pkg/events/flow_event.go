@@ -0,0 +1,127 @@
+package events
+
+// FlowAlertStateEnum is the state of an alert about the health of a flow.
+type FlowAlertStateEnum string
+
+const (
+	FlowAlertStateEnumActivated   FlowAlertStateEnum = "ACTIVATED"
+	FlowAlertStateEnumDeactivated FlowAlertStateEnum = "DEACTIVATED"
+)
+
+// FlowError is the rate of a type of error of a flow, like INVALID_SCREEN_TRANSITION or TIMEOUT.
+type FlowError struct {
+	ErrorType  string
+	ErrorRate  float64
+	ErrorCount int64
+}
+
+// BaseFlowEvent holds the flow a flows notification is about.
+type BaseFlowEvent struct {
+	BaseBusinessAccountEvent
+	FlowId  string
+	Message string // * description of the notification, written by Meta
+}
+
+func NewBaseFlowEvent(baseEvent *BaseBusinessAccountEvent, flowId string, message string) BaseFlowEvent {
+	return BaseFlowEvent{
+		BaseBusinessAccountEvent: *baseEvent,
+		FlowId:                   flowId,
+		Message:                  message,
+	}
+}
+
+// FlowStatusChangeEvent is published when the status of a flow changes, like when Meta moves it to THROTTLED
+// or BLOCKED because its endpoint is unhealthy. The statuses are the values of manager.FlowStatus.
+type FlowStatusChangeEvent struct {
+	BaseFlowEvent
+	OldStatus string
+	NewStatus string
+}
+
+func NewFlowStatusChangeEvent(baseFlowEvent BaseFlowEvent, oldStatus string, newStatus string) *FlowStatusChangeEvent {
+	return &FlowStatusChangeEvent{
+		BaseFlowEvent: baseFlowEvent,
+		OldStatus:     oldStatus,
+		NewStatus:     newStatus,
+	}
+}
+
+// FlowClientErrorRateEvent is published when the rate of the errors of a flow on the WhatsApp clients crosses
+// its threshold, and when it goes back under it.
+type FlowClientErrorRateEvent struct {
+	BaseFlowEvent
+	ErrorRate  float64
+	Threshold  float64
+	AlertState FlowAlertStateEnum
+	Errors     []FlowError
+}
+
+func NewFlowClientErrorRateEvent(baseFlowEvent BaseFlowEvent, errorRate float64, threshold float64, alertState FlowAlertStateEnum, errors []FlowError) *FlowClientErrorRateEvent {
+	return &FlowClientErrorRateEvent{
+		BaseFlowEvent: baseFlowEvent,
+		ErrorRate:     errorRate,
+		Threshold:     threshold,
+		AlertState:    alertState,
+		Errors:        errors,
+	}
+}
+
+// FlowEndpointErrorRateEvent is published when the rate of the errors of the data endpoint of a flow crosses
+// its threshold, and when it goes back under it.
+type FlowEndpointErrorRateEvent struct {
+	BaseFlowEvent
+	ErrorRate  float64
+	Threshold  float64
+	AlertState FlowAlertStateEnum
+	Errors     []FlowError
+}
+
+func NewFlowEndpointErrorRateEvent(baseFlowEvent BaseFlowEvent, errorRate float64, threshold float64, alertState FlowAlertStateEnum, errors []FlowError) *FlowEndpointErrorRateEvent {
+	return &FlowEndpointErrorRateEvent{
+		BaseFlowEvent: baseFlowEvent,
+		ErrorRate:     errorRate,
+		Threshold:     threshold,
+		AlertState:    alertState,
+		Errors:        errors,
+	}
+}
+
+// FlowEndpointLatencyEvent is published when the p90 latency of the data endpoint of a flow crosses its
+// threshold, and when it goes back under it. The latencies and the threshold are in milliseconds.
+type FlowEndpointLatencyEvent struct {
+	BaseFlowEvent
+	P50Latency    int64
+	P90Latency    int64
+	RequestsCount int64
+	Threshold     int64
+	AlertState    FlowAlertStateEnum
+}
+
+func NewFlowEndpointLatencyEvent(baseFlowEvent BaseFlowEvent, p50Latency int64, p90Latency int64, requestsCount int64, threshold int64, alertState FlowAlertStateEnum) *FlowEndpointLatencyEvent {
+	return &FlowEndpointLatencyEvent{
+		BaseFlowEvent: baseFlowEvent,
+		P50Latency:    p50Latency,
+		P90Latency:    p90Latency,
+		RequestsCount: requestsCount,
+		Threshold:     threshold,
+		AlertState:    alertState,
+	}
+}
+
+// FlowEndpointAvailabilityEvent is published when the availability of the data endpoint of a flow, in percent,
+// falls under its threshold, and when it goes back over it.
+type FlowEndpointAvailabilityEvent struct {
+	BaseFlowEvent
+	Availability float64
+	Threshold    float64
+	AlertState   FlowAlertStateEnum
+}
+
+func NewFlowEndpointAvailabilityEvent(baseFlowEvent BaseFlowEvent, availability float64, threshold float64, alertState FlowAlertStateEnum) *FlowEndpointAvailabilityEvent {
+	return &FlowEndpointAvailabilityEvent{
+		BaseFlowEvent: baseFlowEvent,
+		Availability:  availability,
+		Threshold:     threshold,
+		AlertState:    alertState,
+	}
+}
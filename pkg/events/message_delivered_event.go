@@ -2,10 +2,11 @@ package events
 
 // MessageDeliveredEvent represents an event related to an undelivered message.
 type MessageDeliveredEvent struct {
-	BaseSystemEvent `json:",inline"`
-	MessageId       string `json:"messageId"`
-	SentTo          string `json:"sentTo"`
-	SentToUserId    string `json:"sentToUserId,omitempty"` // Business-scoped user ID (BSUID) of the recipient.
+	BaseSystemEvent      `json:",inline"`
+	MessageId            string `json:"messageId"`
+	SentTo               string `json:"sentTo"`
+	SentToUserId         string `json:"sentToUserId,omitempty"` // Business-scoped user ID (BSUID) of the recipient.
+	MessageStatusDetails `json:",inline"`
 }
 
 // MessageDeliveredEvent creates a new instance of MessageUndeliveredEvent.
@@ -2,10 +2,11 @@ package events
 
 // MessageReadEvent represents an event indicating that a message has been read.
 type MessageReadEvent struct {
-	BaseSystemEvent `json:",inline"`
-	MessageId       string `json:"messageId"`
-	SentTo          string `json:"sentTo"`
-	SentToUserId    string `json:"sentToUserId,omitempty"` // Business-scoped user ID (BSUID) of the recipient.
+	BaseSystemEvent      `json:",inline"`
+	MessageId            string `json:"messageId"`
+	SentTo               string `json:"sentTo"`
+	SentToUserId         string `json:"sentToUserId,omitempty"` // Business-scoped user ID (BSUID) of the recipient.
+	MessageStatusDetails `json:",inline"`
 }
 
 // NewMessageReadEvent creates a new instance of MessageReadEvent.
@@ -2,10 +2,11 @@ package events
 
 // MessageSentEvent represents an event indicating that a message has been sent.
 type MessageSentEvent struct {
-	BaseSystemEvent `json:",inline"`
-	MessageId       string `json:"messageId"`
-	SentTo          string `json:"sentTo"`
-	SentToUserId    string `json:"sentToUserId,omitempty"` // Business-scoped user ID (BSUID) of the recipient.
+	BaseSystemEvent      `json:",inline"`
+	MessageId            string `json:"messageId"`
+	SentTo               string `json:"sentTo"`
+	SentToUserId         string `json:"sentToUserId,omitempty"` // Business-scoped user ID (BSUID) of the recipient.
+	MessageStatusDetails `json:",inline"`
 }
 
 // NewMessageSentEvent creates a new instance of MessageSentEvent.
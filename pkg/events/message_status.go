@@ -0,0 +1,23 @@
+package events
+
+// MessageStatusConversation represents the conversation a message is billed in.
+type MessageStatusConversation struct {
+	Id                  string `json:"id"`
+	OriginType          string `json:"originType"`                    // * authentication, marketing, utility, service or referral_conversion
+	ExpirationTimestamp string `json:"expirationTimestamp,omitempty"` // * only known from the sent status of the message opening the conversation
+}
+
+// MessageStatusPricing represents how a message is priced.
+type MessageStatusPricing struct {
+	Billable     bool   `json:"billable"`
+	PricingModel string `json:"pricingModel"`
+	Category     string `json:"category"`
+}
+
+// MessageStatusDetails holds the billing details and the callback data of a message status,
+// shared by the message status events.
+type MessageStatusDetails struct {
+	Conversation          *MessageStatusConversation `json:"conversation,omitempty"`
+	Pricing               *MessageStatusPricing      `json:"pricing,omitempty"`
+	BizOpaqueCallbackData string                     `json:"bizOpaqueCallbackData,omitempty"`
+}
@@ -29,6 +29,17 @@ type MessageTemplateStatusUpdateEvent struct {
 	MessageTemplateName     string
 	MessageTemplateLanguage string
 	Reason                  MessageTemplateStatusUpdateReason
+	DisableInfo             *MessageTemplateStatusUpdateDisableInfo
+	OtherInfo               *MessageTemplateStatusUpdateOtherInfo
+}
+
+type MessageTemplateStatusUpdateDisableInfo struct {
+	DisableDate string
+}
+
+type MessageTemplateStatusUpdateOtherInfo struct {
+	Title       string
+	Description string
 }
 
 func NewMessageTemplateStatusUpdateEvent(baseEvent *BaseBusinessAccountEvent, event MessageTemplateStatusUpdateEventEnum, messageTemplateId int64, messageTemplateName string, messageTemplateLanguage string, reason MessageTemplateStatusUpdateReason) *MessageTemplateStatusUpdateEvent {
@@ -26,6 +26,7 @@ type PhoneNumberQualityUpdateEvent struct {
 	DisplayPhoneNumber string
 	Event              PhoneNumberUpdateEventEnum
 	CurrentLimit       PhoneNumberQualityUpdateCurrentLimitEnum
+	OldLimit           PhoneNumberQualityUpdateCurrentLimitEnum
 }
 
 func NewPhoneNumberQualityUpdateEvent(baseEvent *BaseBusinessAccountEvent, displayPhoneNumber string, event PhoneNumberUpdateEventEnum, currentLimit PhoneNumberQualityUpdateCurrentLimitEnum) *PhoneNumberQualityUpdateEvent {
@@ -2,8 +2,20 @@ package events
 
 type SecurityEvent struct {
 	BaseBusinessAccountEvent
+	DisplayPhoneNumber string
+	Event              string
+	Requester          string
 }
 
 func NewSecurity() *SecurityEvent {
 	return &SecurityEvent{}
 }
+
+func NewSecurityEvent(baseEvent *BaseBusinessAccountEvent, displayPhoneNumber string, event string, requester string) *SecurityEvent {
+	return &SecurityEvent{
+		BaseBusinessAccountEvent: *baseEvent,
+		DisplayPhoneNumber:       displayPhoneNumber,
+		Event:                    event,
+		Requester:                requester,
+	}
+}
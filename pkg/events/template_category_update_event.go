@@ -17,6 +17,7 @@ type TemplateCategoryUpdateEvent struct {
 	MessageTemplateLanguage string
 	PreviousCategory        MessageTemplateCategoryEnum
 	NewCategory             MessageTemplateCategoryEnum
+	CorrectCategory         MessageTemplateCategoryEnum
 }
 
 func NewMessageTemplateCategoryUpdateEvent(baseEvent *BaseBusinessAccountEvent, messageTemplateId int64, messageTemplateName string, messageTemplateLanguage string, previousCategory MessageTemplateCategoryEnum, newCategory MessageTemplateCategoryEnum) *TemplateCategoryUpdateEvent {
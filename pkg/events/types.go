@@ -15,6 +15,7 @@ const (
 	TemplateMessageEventType              EventType = "template_message"
 	QuickReplyMessageEventType            EventType = "quick_reply_message"
 	ReplyButtonInteractionEventType       EventType = "reply_button_interaction"
+	FlowCompletionEventType               EventType = "flow_completion"
 	StickerMessageEventType               EventType = "sticker_message"
 	AdInteractionEventType                EventType = "ad_interaction_message"
 	CustomerIdentityChangedEventType      EventType = "customer_identity_changed"
@@ -32,6 +33,7 @@ const (
 	ReadyEventType                        EventType = "ready"
 	MessageTemplateStatusUpdateEventType  EventType = "message_template_status_update"
 	MessageTemplateQualityUpdateEventType EventType = "message_template_quality_update"
+	TemplateCategoryUpdateEventType       EventType = "template_category_update"
 	PhoneNumberNameUpdateEventType        EventType = "phone_number_name_update"
 	PhoneNumberQualityUpdateEventType     EventType = "phone_number_quality_update"
 	SecurityEventType                     EventType = "security"
@@ -39,5 +41,10 @@ const (
 	AccountReviewUpdateEventType          EventType = "account_review_update"
 	AccountAlertsEventType                EventType = "account_alerts"
 	BusinessCapabilityUpdateEventType     EventType = "business_capability_update"
+	FlowStatusChangeEventType             EventType = "flow_status_change"
+	FlowClientErrorRateEventType          EventType = "flow_client_error_rate"
+	FlowEndpointErrorRateEventType        EventType = "flow_endpoint_error_rate"
+	FlowEndpointLatencyEventType          EventType = "flow_endpoint_latency"
+	FlowEndpointAvailabilityEventType     EventType = "flow_endpoint_availability"
 	MarketingMessagesLinkClickEventType   EventType = "marketing_messages_link_click"
 )
@@ -1,6 +1,7 @@
 package messaging
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strings"
@@ -11,9 +12,11 @@ import (
 
 // MessagingClient represents a WhatsApp client.
 type MessagingClient struct {
-	Media             manager.MediaManager
-	Message           manager.MessageManager
-	PhoneNumberId     string
+	Media         manager.MediaManager
+	Message       manager.MessageManager
+	PhoneNumberId string
+	// Deprecated: ApiAccessToken is only read when Requester is nil, use GetApiAccessToken for the token the
+	// requests are sent with.
 	ApiAccessToken    string
 	BusinessAccountId string
 	Requester         *request_client.RequestClient
@@ -29,12 +32,23 @@ func (client *MessagingClient) SetPhoneNumberId(phoneNumberId string) {
 	client.PhoneNumberId = phoneNumberId
 }
 
+// GetApiAccessToken returns the access token the requests of the messaging client are currently sent with.
 func (client *MessagingClient) GetApiAccessToken() string {
-	return client.ApiAccessToken
+	if client.Requester == nil {
+		return client.ApiAccessToken
+	}
+	return client.Requester.ApiAccessToken()
 }
 
+// SetApiAccessToken makes the messaging client, and its message and media managers, send their requests with
+// the given access token, leaving the token of the other messaging clients untouched.
 func (client *MessagingClient) SetApiAccessToken(apiAccessToken string) {
 	client.ApiAccessToken = apiAccessToken
+	client.Message.SetAccessToken(apiAccessToken)
+	client.Media.SetAccessToken(apiAccessToken)
+	if client.Requester != nil {
+		client.Requester = client.Requester.CloneWithTokenProvider(manager.StaticTokenProvider(apiAccessToken))
+	}
 }
 
 func (client *MessagingClient) GetBusinessAccountId() string {
@@ -47,10 +61,15 @@ type RegisterResponse struct {
 
 // this register function is for one time registration of the phone number to enable the usage with WhatsApp Cloud API
 func (client *MessagingClient) Register(pin string) (RegisterResponse, error) {
+	return client.RegisterContext(context.Background(), pin)
+}
+
+// RegisterContext is like Register but uses ctx for the requests made to the Graph API.
+func (client *MessagingClient) RegisterContext(ctx context.Context, pin string) (RegisterResponse, error) {
 	apiRequest := client.Requester.NewApiRequest(strings.Join([]string{client.PhoneNumberId, "resgiter"}, "/"), http.MethodPost)
 	apiRequest.AddQueryParam("messaging_product", "WHATSAPP")
 	apiRequest.AddQueryParam("pin", pin)
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return RegisterResponse{}, err
 	}
@@ -60,8 +79,13 @@ func (client *MessagingClient) Register(pin string) (RegisterResponse, error) {
 }
 
 func (client *MessagingClient) Deregister() (RegisterResponse, error) {
+	return client.DeregisterContext(context.Background())
+}
+
+// DeregisterContext is like Deregister but uses ctx for the requests made to the Graph API.
+func (client *MessagingClient) DeregisterContext(ctx context.Context) (RegisterResponse, error) {
 	apiRequest := client.Requester.NewApiRequest(strings.Join([]string{client.PhoneNumberId, "deregister"}, "/"), http.MethodPost)
-	response, err := apiRequest.Execute()
+	response, err := apiRequest.Execute(ctx)
 	if err != nil {
 		return RegisterResponse{}, err
 	}